@@ -0,0 +1,138 @@
+package checks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// v1Adapter adapts a CheckInterfaceV2 to CheckInterface.
+type v1Adapter struct {
+	check CheckInterfaceV2
+	mtx   sync.RWMutex
+	last  Result
+}
+
+// v2Adapter adapts a CheckInterface to CheckInterfaceV2.
+type v2Adapter struct {
+	check CheckInterface
+}
+
+// FromV2 returns c as a CheckInterface.
+// The returned check reports the state of the last Result returned by c.
+// If c already implements CheckInterface it is returned as is.
+func FromV2(c CheckInterfaceV2) CheckInterface {
+	if c == nil {
+		return nil
+	}
+
+	if v1, ok := c.(CheckInterface); ok {
+		return v1
+	}
+
+	if a, ok := c.(*v2Adapter); ok {
+		return a.check
+	}
+
+	return &v1Adapter{
+		check: c,
+		last: Result{
+			Status:    STARTUP,
+			Timestamp: time.Unix(0, 0),
+		},
+	}
+}
+
+// ToV2 returns c as a CheckInterfaceV2.
+// The context is passed down to c if it implements ContextCheckInterface.
+// If c already implements CheckInterfaceV2 it is returned as is.
+func ToV2(c CheckInterface) CheckInterfaceV2 {
+	if c == nil {
+		return nil
+	}
+
+	if v2, ok := c.(CheckInterfaceV2); ok {
+		return v2
+	}
+
+	if a, ok := c.(*v1Adapter); ok {
+		return a.check
+	}
+
+	return &v2Adapter{
+		check: c,
+	}
+}
+
+func (c *v1Adapter) GetImp() Implementation {
+	return c.check.GetImp()
+}
+
+func (c *v1Adapter) GetStatus() CheckStatus {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.last.Status
+}
+
+func (c *v1Adapter) GetLastCheck() time.Time {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.last.Timestamp
+}
+
+func (c *v1Adapter) GetError() error {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.last.Err
+}
+
+func (c *v1Adapter) HealthCheck() error {
+	return c.HealthCheckContext(context.Background())
+}
+
+func (c *v1Adapter) HealthCheckContext(ctx context.Context) error {
+	c.mtx.Lock()
+	c.last.Status = CHECKING
+	c.mtx.Unlock()
+
+	res := c.check.Check(ctx)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.last = res
+	return res.Err
+}
+
+func (c *v1Adapter) Cleanup() {
+	c.check.Cleanup()
+}
+
+func (c *v2Adapter) GetImp() Implementation {
+	return c.check.GetImp()
+}
+
+func (c *v2Adapter) Check(ctx context.Context) Result {
+	start := time.Now()
+
+	var err error
+	if cc, ok := c.check.(ContextCheckInterface); ok {
+		err = cc.HealthCheckContext(ctx)
+	} else {
+		err = c.check.HealthCheck()
+	}
+
+	return Result{
+		Status:    c.check.GetStatus(),
+		Err:       err,
+		Latency:   time.Since(start),
+		Timestamp: c.check.GetLastCheck(),
+	}
+}
+
+func (c *v2Adapter) Cleanup() {
+	c.check.Cleanup()
+}
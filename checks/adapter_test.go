@@ -0,0 +1,108 @@
+package checks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const testV2 Implementation = "test-v2"
+
+var errTestV2 = errors.New("v2 check is configured to fail")
+
+type testV2Check struct {
+	fail bool
+}
+
+func (c *testV2Check) GetImp() Implementation {
+	return testV2
+}
+
+func (c *testV2Check) Check(ctx context.Context) Result {
+	res := Result{
+		Status:    DONE,
+		Latency:   time.Millisecond,
+		Timestamp: time.Now(),
+		Details:   map[string]any{"fail": c.fail},
+	}
+
+	if c.fail {
+		res.Err = errTestV2
+	}
+
+	return res
+}
+
+func (c *testV2Check) Cleanup() {}
+
+func TestFromV2(t *testing.T) {
+	t.Parallel()
+
+	aCheck := FromV2(&testV2Check{fail: true})
+	defer aCheck.Cleanup()
+
+	if aCheck.GetImp() != testV2 {
+		t.Fatalf("FromV2().GetImp() returned unexpected value: %s", aCheck.GetImp())
+	}
+
+	if aCheck.GetStatus() != STARTUP {
+		t.Fatalf("FromV2().GetStatus() returned unexpected value after initialisation: %s", aCheck.GetStatus())
+	}
+
+	if aCheck.GetLastCheck().Equal(time.Time{}) {
+		t.Fatalf("FromV2().GetLastCheck() returned unexpected value after initialisation: %v", aCheck.GetLastCheck())
+	}
+
+	err := aCheck.HealthCheck()
+	if !errors.Is(err, errTestV2) {
+		t.Fatalf("FromV2().HealthCheck() did not return expected error\nexpected: %v\ngot: %v", errTestV2, err)
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("FromV2().GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if !errors.Is(aCheck.GetError(), errTestV2) {
+		t.Fatalf("FromV2().GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+}
+
+func TestToV2(t *testing.T) {
+	t.Parallel()
+
+	aCheck := ToV2(FromV2(&testV2Check{}))
+	if _, ok := aCheck.(*testV2Check); !ok {
+		t.Fatalf("ToV2(FromV2()) did not return the original check: %T", aCheck)
+	}
+
+	hCheck, err := NewHttpCheck("localhost", testHttpPort, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck = ToV2(hCheck)
+	defer aCheck.Cleanup()
+
+	if aCheck.GetImp() != HTTP {
+		t.Fatalf("ToV2().GetImp() returned unexpected value: %s", aCheck.GetImp())
+	}
+
+	res := aCheck.Check(context.Background())
+	if res.Err == nil {
+		t.Fatalf("ToV2().Check() did not return an error for a failing check")
+	}
+
+	if res.Status != DONE {
+		t.Fatalf("ToV2().Check() returned unexpected status: %s", res.Status)
+	}
+
+	if res.Latency <= 0 {
+		t.Fatalf("ToV2().Check() returned unexpected latency: %v", res.Latency)
+	}
+
+	if !(res.Timestamp.Before(time.Now()) && res.Timestamp.After(time.Unix(0, 0))) {
+		t.Fatalf("ToV2().Check() returned unexpected timestamp: %v", res.Timestamp)
+	}
+}
@@ -0,0 +1,85 @@
+package checks
+
+import (
+	"sync"
+	"time"
+)
+
+// BaseCheck holds the state common to all checks and is safe for concurrent use.
+// Embedding a *BaseCheck, created with NewBaseCheck, provides a check with
+// GetStatus, GetLastCheck and GetError, which may be called whilst the check runs.
+type BaseCheck struct {
+	mtx       sync.RWMutex
+	status    CheckStatus
+	lastCheck time.Time
+	err       error
+}
+
+// NewBaseCheck returns a new BaseCheck in the STARTUP status.
+func NewBaseCheck() *BaseCheck {
+	return &BaseCheck{
+		status:    STARTUP,
+		lastCheck: time.Unix(0, 0),
+		err:       nil,
+	}
+}
+
+func (b *BaseCheck) GetStatus() CheckStatus {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	return b.status
+}
+
+func (b *BaseCheck) GetLastCheck() time.Time {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	return b.lastCheck
+}
+
+func (b *BaseCheck) GetError() error {
+	b.mtx.RLock()
+	defer b.mtx.RUnlock()
+
+	return b.err
+}
+
+// SetStatus sets the check's current status.
+func (b *BaseCheck) SetStatus(status CheckStatus) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.status = status
+}
+
+// Done records the outcome of a check attempt and marks the check as DONE.
+// Returns err, so a check can finish with `return c.Done(err)`.
+func (b *BaseCheck) Done(err error) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.err = err
+	b.lastCheck = time.Now()
+	b.status = DONE
+	return err
+}
+
+// DoneResult records the outcome of a check attempt which began at start, as Done,
+// and returns it as a Result, so a CheckInterfaceV2 check can finish with
+// `return c.DoneResult(start, err, details)`.
+func (b *BaseCheck) DoneResult(start time.Time, err error, details map[string]any) Result {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	b.err = err
+	b.lastCheck = time.Now()
+	b.status = DONE
+	return Result{
+		Status:    b.status,
+		Err:       err,
+		Latency:   b.lastCheck.Sub(start),
+		Timestamp: b.lastCheck,
+		Details:   details,
+	}
+}
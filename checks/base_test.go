@@ -0,0 +1,101 @@
+package checks
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+func TestNewBaseCheck(t *testing.T) {
+	t.Parallel()
+
+	aBase := NewBaseCheck()
+
+	if aBase.GetStatus() != STARTUP {
+		t.Fatalf("BaseCheck.GetStatus() returned unexpected value after initialisation: %s", aBase.GetStatus())
+	}
+
+	if aBase.GetLastCheck().Equal(time.Time{}) {
+		t.Fatalf("BaseCheck.GetLastCheck() returned unexpected value after initialisation: %v", aBase.GetLastCheck())
+	}
+
+	if aBase.GetError() != nil {
+		t.Fatalf("BaseCheck.GetError() returned unexpected value after initialisation: %v", aBase.GetError())
+	}
+}
+
+func TestBaseCheckDone(t *testing.T) {
+	t.Parallel()
+
+	aBase := NewBaseCheck()
+	aErr := errors.New("wibble")
+
+	aBase.SetStatus(CHECKING)
+	if aBase.GetStatus() != CHECKING {
+		t.Fatalf("BaseCheck.GetStatus() returned unexpected value after calling SetStatus(): %s", aBase.GetStatus())
+	}
+
+	err := aBase.Done(aErr)
+	if err != aErr {
+		t.Fatalf("BaseCheck.Done() did not return the error provided\nexpected: %v\ngot: %v", aErr, err)
+	}
+
+	if aBase.GetStatus() != DONE {
+		t.Fatalf("BaseCheck.GetStatus() returned unexpected value after calling Done(): %s", aBase.GetStatus())
+	}
+
+	if !(aBase.GetLastCheck().Before(time.Now()) && aBase.GetLastCheck().After(time.Unix(0, 0))) {
+		t.Fatalf("BaseCheck.GetLastCheck() returned unexpected value after calling Done(): %v", aBase.GetLastCheck())
+	}
+
+	if aBase.GetError() != aErr {
+		t.Fatalf("BaseCheck.GetError() returned unexpected value after calling Done(): %v", aBase.GetError())
+	}
+}
+
+func TestBaseCheckConcurrentAccess(t *testing.T) {
+	t.Parallel()
+
+	aBase := NewBaseCheck()
+
+	// Run with -race, concurrent reads and writes must not race.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			aBase.SetStatus(CHECKING)
+			aBase.Done(nil)
+			wg.Done()
+		}()
+		go func() {
+			aBase.GetStatus()
+			aBase.GetLastCheck()
+			aBase.GetError()
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+}
+
+func TestBaseCheckDoneResult(t *testing.T) {
+	t.Parallel()
+
+	aBase := NewBaseCheck()
+	aErr := errors.New("wibble")
+	start := time.Now()
+
+	res := aBase.DoneResult(start, aErr, map[string]any{"foo": "bar"})
+	if res.Err != aErr || res.Status != DONE || res.Details["foo"] != "bar" {
+		t.Fatalf("BaseCheck.DoneResult() returned unexpected result: %+v", res)
+	}
+
+	if res.Latency <= 0 || !res.Timestamp.Equal(aBase.GetLastCheck()) {
+		t.Fatalf("BaseCheck.DoneResult() returned unexpected latency or timestamp: %+v", res)
+	}
+
+	if aBase.GetError() != aErr || aBase.GetStatus() != DONE {
+		t.Fatalf("BaseCheck.DoneResult() did not record the outcome: %v %s", aBase.GetError(), aBase.GetStatus())
+	}
+}
@@ -1,6 +1,13 @@
 package checks
 
-import "time"
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidConfig = errors.New("invalid check configuration")
 
 type Implementation string
 type CheckStatus string
@@ -31,3 +38,38 @@ type CheckInterface interface {
 	// Cleans up any resources and dependencies required by the check.
 	Cleanup()
 }
+
+// ContextCheckInterface is implemented by checks which can be cancelled.
+type ContextCheckInterface interface {
+	CheckInterface
+	// HealthCheckContext runs the check, giving up once ctx is done.
+	// If the check fails, returns the error encountered.
+	// If the check succeeds returns nil.
+	HealthCheckContext(ctx context.Context) error
+}
+
+// Result is the outcome of a single check attempt.
+type Result struct {
+	// Status is the check's status once the attempt finished.
+	Status CheckStatus
+	// Err is the error the attempt encountered, nil if the check passed.
+	Err error
+	// Latency is how long the attempt took.
+	Latency time.Duration
+	// Timestamp is the time the attempt finished.
+	Timestamp time.Time
+	// Details holds any check specific information about the attempt.
+	Details map[string]any
+}
+
+// CheckInterfaceV2 is a context aware check which reports a structured Result.
+// Use FromV2 and ToV2 to convert between CheckInterface and CheckInterfaceV2.
+type CheckInterfaceV2 interface {
+	// GetImp returns the check's Implementation.
+	GetImp() Implementation
+	// Check runs the check, giving up once ctx is done.
+	// Returns the outcome of the attempt, Result.Err is nil if the check succeeds.
+	Check(ctx context.Context) Result
+	// Cleans up any resources and dependencies required by the check.
+	Cleanup()
+}
@@ -1,32 +1,180 @@
 package checks
 
 import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"regexp"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
-const HTTP Implementation = "http"
+const (
+	HTTP Implementation = "http"
+
+	// maxHttpBodySize limits how much of a response body is read, for assertions and
+	// when draining it.
+	maxHttpBodySize = 1 << 20
+)
+
+var ErrUnexpectedHttpStatus = errors.New("unexpected http response status")
+var ErrHttpBodyAssertion = errors.New("http response body assertion failed")
+var ErrHttpBodyTooLarge = errors.New("http response body exceeds 1 MiB")
 
 type httpCheck struct {
-	status    CheckStatus
-	lastCheck time.Time
-	err       error
-	host      string
-	port      int
-	timeout   time.Duration
+	*BaseCheck
+	url            string
+	timeout        time.Duration
+	method         string
+	header         http.Header
+	body           []byte
+	expectedStatus []int
+	tlsConfig      *tls.Config
+	assertions     []httpBodyAssertion
+	client         *http.Client
+}
+
+// httpBodyAssertion returns an error describing why body is unhealthy, or nil.
+type httpBodyAssertion func(body []byte) error
+
+// HttpOption configures a http check.
+type HttpOption func(*httpCheck)
+
+// WithHttpMethod sets the request method, defaults to GET.
+func WithHttpMethod(method string) HttpOption {
+	return func(c *httpCheck) {
+		c.method = method
+	}
+}
+
+// WithHttpHeader adds a header to the request, may be used multiple times.
+func WithHttpHeader(key, value string) HttpOption {
+	return func(c *httpCheck) {
+		c.header.Add(key, value)
+	}
+}
+
+// WithHttpBody sets the request body.
+func WithHttpBody(body []byte) HttpOption {
+	return func(c *httpCheck) {
+		c.body = body
+	}
+}
+
+// WithHttpExpectedStatus sets the response status codes which count as healthy,
+// defaults to any 2xx status.
+func WithHttpExpectedStatus(codes ...int) HttpOption {
+	return func(c *httpCheck) {
+		c.expectedStatus = codes
+	}
+}
+
+// WithHttpTLSConfig sets the TLS configuration used for https requests,
+// e.g. to trust a private CA or present a client certificate.
+func WithHttpTLSConfig(cfg *tls.Config) HttpOption {
+	return func(c *httpCheck) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithHttpBodyContains asserts that the response body contains substr.
+func WithHttpBodyContains(substr string) HttpOption {
+	return func(c *httpCheck) {
+		c.assertions = append(c.assertions, func(body []byte) error {
+			if !bytes.Contains(body, []byte(substr)) {
+				return errors.Wrapf(ErrHttpBodyAssertion, "response body does not contain %q", substr)
+			}
+			return nil
+		})
+	}
+}
+
+// WithHttpBodyRegexp asserts that the response body matches re.
+func WithHttpBodyRegexp(re *regexp.Regexp) HttpOption {
+	return func(c *httpCheck) {
+		c.assertions = append(c.assertions, func(body []byte) error {
+			if !re.Match(body) {
+				return errors.Wrapf(ErrHttpBodyAssertion, "response body does not match %q", re.String())
+			}
+			return nil
+		})
+	}
+}
+
+// WithHttpJSONPath asserts that the response body is JSON and the value at path equals expected.
+// Paths are object keys separated by dots and array indices in brackets, e.g. "$.checks[0].status".
+// Values are compared as JSON, so expected may be any value which encoding/json can marshal.
+func WithHttpJSONPath(path string, expected any) HttpOption {
+	return func(c *httpCheck) {
+		c.assertions = append(c.assertions, func(body []byte) error {
+			var doc any
+			err := json.Unmarshal(body, &doc)
+			if err != nil {
+				return errors.Wrapf(ErrHttpBodyAssertion, "response body is not json: %v", err)
+			}
+
+			actual, err := jsonPathLookup(doc, path)
+			if err != nil {
+				return errors.Wrap(ErrHttpBodyAssertion, err.Error())
+			}
+
+			want, err := jsonNormalise(expected)
+			if err != nil {
+				return errors.Wrapf(ErrHttpBodyAssertion, "cannot compare json path %q with %v: %v", path, expected, err)
+			}
+
+			if !reflect.DeepEqual(actual, want) {
+				return errors.Wrapf(ErrHttpBodyAssertion, "json path %q is %v, expected %v", path, actual, want)
+			}
+			return nil
+		})
+	}
 }
 
-func NewHttpCheck(host string, port int, timeout time.Duration) (CheckInterface, error) {
+// NewHttpCheck returns a check which requests http://host:port.
+func NewHttpCheck(host string, port int, timeout time.Duration, opts ...HttpOption) (CheckInterface, error) {
+	return NewHttpURLCheck(fmt.Sprintf("http://%s:%d", host, port), timeout, opts...)
+}
+
+// NewHttpURLCheck returns a check which requests rawURL, which must be a http or https URL.
+// The response status code and latency are reported in the check's details. Body
+// assertions fail with ErrHttpBodyTooLarge for bodies over 1 MiB.
+func NewHttpURLCheck(rawURL string, timeout time.Duration, opts ...HttpOption) (CheckInterface, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "error parsing http check url")
+	}
+
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.Wrapf(ErrInvalidConfig, "http check url must be an absolute http or https url: %q", rawURL)
+	}
+
 	check := httpCheck{
-		status:    STARTUP,
-		lastCheck: time.Unix(0, 0),
-		err:       nil,
-		host:      host,
-		port:      port,
+		BaseCheck: NewBaseCheck(),
+		url:       u.String(),
 		timeout:   timeout,
+		method:    http.MethodGet,
+		header:    make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if check.tlsConfig != nil {
+		transport.TLSClientConfig = check.tlsConfig
+	}
+	check.client = &http.Client{
+		Timeout:   check.timeout,
+		Transport: transport,
 	}
 
 	return &check, nil
@@ -36,39 +184,87 @@ func (c *httpCheck) GetImp() Implementation {
 	return HTTP
 }
 
-func (c *httpCheck) GetStatus() CheckStatus {
-	return c.status
+func (c *httpCheck) HealthCheck() error {
+	return c.HealthCheckContext(context.Background())
 }
 
-func (c *httpCheck) GetLastCheck() time.Time {
-	return c.lastCheck
+func (c *httpCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
 }
 
-func (c *httpCheck) GetError() error {
-	return c.err
-}
+func (c *httpCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(STARTUP)
 
-func (c *httpCheck) HealthCheck() error {
-	c.status = STARTUP
+	var body io.Reader
+	if c.body != nil {
+		body = bytes.NewReader(c.body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, c.method, c.url, body)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrapf(err, "error creating http %s request", c.method), nil)
+	}
+	req.Header = c.header.Clone()
+
+	c.SetStatus(CHECKING)
+
+	reqStart := time.Now()
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrapf(err, "error making http %s request", c.method), nil)
+	}
+	defer func() {
+		// Draining the body, even when it is not asserted on, allows the connection to be reused.
+		io.Copy(io.Discard, io.LimitReader(resp.Body, maxHttpBodySize))
+		resp.Body.Close()
+	}()
+
+	details := map[string]any{
+		"status_code":      resp.StatusCode,
+		"response_latency": time.Since(reqStart).String(),
+	}
+
+	if !c.isExpectedStatus(resp.StatusCode) {
+		return c.DoneResult(start, errors.Wrapf(ErrUnexpectedHttpStatus, "%s %s returned %s", c.method, c.url, resp.Status), details)
+	}
 
-	urlStr := fmt.Sprintf("http://%s:%d", c.host, c.port)
-	client := http.Client{
-		Timeout: c.timeout,
+	if len(c.assertions) == 0 {
+		return c.DoneResult(start, nil, details)
 	}
 
-	c.status = CHECKING
+	// Reading one byte beyond the limit tells a body at the limit from a larger one.
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxHttpBodySize+1))
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error reading http response body"), details)
+	}
+	if len(respBody) > maxHttpBodySize {
+		return c.DoneResult(start, errors.Wrapf(ErrHttpBodyTooLarge, "%s %s", c.method, c.url), details)
+	}
+
+	for _, assertion := range c.assertions {
+		if err := assertion(respBody); err != nil {
+			return c.DoneResult(start, err, details)
+		}
+	}
 
-	_, c.err = client.Get(urlStr)
-	if c.err != nil {
-		c.err = errors.Wrap(c.err, "error making http GET request")
-		c.lastCheck = time.Now()
-		c.status = DONE
-		return c.err
+	return c.DoneResult(start, nil, details)
+}
+
+// isExpectedStatus returns whether code counts as healthy.
+func (c *httpCheck) isExpectedStatus(code int) bool {
+	if len(c.expectedStatus) == 0 {
+		return code >= 200 && code < 300
 	}
 
-	c.lastCheck = time.Now()
-	c.status = DONE
-	return c.err
+	for _, expected := range c.expectedStatus {
+		if code == expected {
+			return true
+		}
+	}
+	return false
 }
 
-func (c *httpCheck) Cleanup() {}
+func (c *httpCheck) Cleanup() {
+	c.client.CloseIdleConnections()
+}
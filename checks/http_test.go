@@ -1,8 +1,14 @@
 package checks
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"testing"
 	"time"
 
@@ -12,20 +18,30 @@ import (
 const testHttpPort = 4560
 
 func testHttpServer(port int, shutdown chan bool) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	})
+
 	server := &http.Server{
-		Addr: fmt.Sprintf(":%d", port),
+		Addr:    fmt.Sprintf(":%d", port),
+		Handler: mux,
 	}
 
-	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(200)
-	})
+	// Listening before returning ensures the server is ready for the check.
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		panic(err)
+	}
 
 	go func() {
 		<-shutdown
 		server.Close()
+		// Acknowledge the shutdown, the port is free once this is received.
+		shutdown <- true
 	}()
 
-	go server.ListenAndServe()
+	go server.Serve(listener)
 }
 
 func TestNewHttpCheck(t *testing.T) {
@@ -58,7 +74,10 @@ func TestHttpHealthCheck(t *testing.T) {
 	var shutdown chan bool = make(chan bool)
 
 	testHttpServer(testHttpPort, shutdown)
-	defer func() { shutdown <- true }()
+	defer func() {
+		shutdown <- true
+		<-shutdown
+	}()
 
 	aCheck, err := NewHttpCheck("localhost", testHttpPort, 10*time.Second)
 	if err != nil {
@@ -110,3 +129,186 @@ func TestHttpHealthCheckNoServer(t *testing.T) {
 		t.Fatalf("httpCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
 	}
 }
+
+func TestHttpHealthCheckContextCancelled(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewHttpCheck("localhost", testHttpPort, 10*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = aCheck.(ContextCheckInterface).HealthCheckContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("httpCheck.HealthCheckContext() did not return expected error for a cancelled context\nexpected: %v\ngot: %v", context.Canceled, err)
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("httpCheck.GetStatus() returned unexpected value after calling HealthCheckContext(): %s", aCheck.GetStatus())
+	}
+}
+
+func TestNewHttpURLCheckInvalid(t *testing.T) {
+	t.Parallel()
+
+	for _, rawURL := range []string{"localhost:80", "ftp://localhost", "https://", "http://[::1"} {
+		_, err := NewHttpURLCheck(rawURL, time.Second)
+		if err == nil {
+			t.Fatalf("NewHttpURLCheck() did not return an error for invalid url %q", rawURL)
+		}
+	}
+}
+
+func TestHttpHealthCheckUnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	aCheck, err := NewHttpURLCheck(server.URL, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	res := ToV2(aCheck).Check(context.Background())
+	if !errors.Is(res.Err, ErrUnexpectedHttpStatus) {
+		t.Fatalf("httpCheck.Check() did not return expected error for a 500 response\nexpected: %v\ngot: %v", ErrUnexpectedHttpStatus, res.Err)
+	}
+
+	if res.Details["status_code"] != http.StatusInternalServerError || res.Details["response_latency"] == nil {
+		t.Fatalf("httpCheck.Check() returned unexpected details: %v", res.Details)
+	}
+
+	aCheck, err = NewHttpURLCheck(server.URL, time.Second, WithHttpExpectedStatus(http.StatusOK, http.StatusInternalServerError))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatalf("httpCheck.HealthCheck() returned an error for an expected status: %v", err)
+	}
+}
+
+func TestHttpHealthCheckRequest(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/v1/ping" || r.Header.Get("Authorization") != "Bearer wibble" || string(body) != "foo" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	aCheck, err := NewHttpURLCheck(server.URL+"/v1/ping", time.Second,
+		WithHttpMethod(http.MethodPost),
+		WithHttpHeader("Authorization", "Bearer wibble"),
+		WithHttpBody([]byte("foo")),
+		WithHttpTLSConfig(server.Client().Transport.(*http.Transport).TLSClientConfig),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	// Run twice, the request body must be sent on every attempt.
+	for i := 0; i < 2; i++ {
+		err = aCheck.HealthCheck()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("httpCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+}
+
+func TestHttpHealthCheckBodyAssertions(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"degraded","version":"1.2.3","checks":[{"name":"db","count":3}]}`))
+	}))
+	defer server.Close()
+
+	for _, tc := range []struct {
+		name   string
+		opt    HttpOption
+		passes bool
+	}{
+		{"contains", WithHttpBodyContains(`"version"`), true},
+		{"not contains", WithHttpBodyContains(`"healthy"`), false},
+		{"regexp", WithHttpBodyRegexp(regexp.MustCompile(`"version":"1\.\d+\.\d+"`)), true},
+		{"not regexp", WithHttpBodyRegexp(regexp.MustCompile(`"version":"2\.`)), false},
+		{"json path", WithHttpJSONPath("$.checks[0].count", 3), true},
+		{"json path string", WithHttpJSONPath("checks[0].name", "db"), true},
+		{"json path unexpected", WithHttpJSONPath("$.status", "ok"), false},
+		{"json path missing", WithHttpJSONPath("$.checks[1].name", "db"), false},
+	} {
+		aCheck, err := NewHttpURLCheck(server.URL, time.Second, tc.opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = aCheck.HealthCheck()
+		if tc.passes && err != nil {
+			t.Fatalf("%s: httpCheck.HealthCheck() returned unexpected error: %v", tc.name, err)
+		}
+
+		if !tc.passes && !errors.Is(err, ErrHttpBodyAssertion) {
+			t.Fatalf("%s: httpCheck.HealthCheck() did not return expected error\nexpected: %v\ngot: %v", tc.name, ErrHttpBodyAssertion, err)
+		}
+
+		if !errors.Is(aCheck.GetError(), err) {
+			t.Fatalf("%s: httpCheck.GetError() returned unexpected value after calling HealthCheck(): %v", tc.name, aCheck.GetError())
+		}
+
+		aCheck.Cleanup()
+	}
+}
+
+func TestHttpHealthCheckBodyTooLarge(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"status":"ok","padding":"`))
+		w.Write(bytes.Repeat([]byte("x"), maxHttpBodySize))
+		w.Write([]byte(`"}`))
+	}))
+	defer server.Close()
+
+	aCheck, err := NewHttpURLCheck(server.URL, time.Second, WithHttpJSONPath("$.status", "ok"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if !errors.Is(err, ErrHttpBodyTooLarge) {
+		t.Fatalf("httpCheck.HealthCheck() did not return expected error for a large body\nexpected: %v\ngot: %v", ErrHttpBodyTooLarge, err)
+	}
+
+	aCheck, err = NewHttpURLCheck(server.URL, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatalf("httpCheck.HealthCheck() returned an error for a large body without assertions: %v", err)
+	}
+}
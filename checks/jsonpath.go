@@ -0,0 +1,79 @@
+package checks
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// jsonPathLookup returns the value at path within doc, a value decoded by encoding/json.
+// Paths are a simple subset of JSONPath, object keys separated by dots and array indices
+// in brackets, with an optional leading "$", e.g. "$.checks[0].status" or "status".
+func jsonPathLookup(doc any, path string) (any, error) {
+	path = strings.TrimPrefix(path, "$")
+
+	value := doc
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+
+		key := segment
+		var indices []string
+		if i := strings.Index(segment, "["); i >= 0 {
+			key = segment[:i]
+			for _, index := range strings.Split(segment[i+1:], "[") {
+				if !strings.HasSuffix(index, "]") {
+					return nil, errors.Errorf("invalid json path segment %q", segment)
+				}
+				indices = append(indices, strings.TrimSuffix(index, "]"))
+			}
+		}
+
+		if key != "" {
+			obj, ok := value.(map[string]any)
+			if !ok {
+				return nil, errors.Errorf("json path %q: %q is not an object", path, key)
+			}
+
+			value, ok = obj[key]
+			if !ok {
+				return nil, errors.Errorf("json path %q: key %q not found", path, key)
+			}
+		}
+
+		for _, index := range indices {
+			n, err := strconv.Atoi(index)
+			if err != nil {
+				return nil, errors.Errorf("json path %q: invalid index %q", path, index)
+			}
+
+			arr, ok := value.([]any)
+			if !ok {
+				return nil, errors.Errorf("json path %q: value at index %d is not an array", path, n)
+			}
+
+			if n < 0 || n >= len(arr) {
+				return nil, errors.Errorf("json path %q: index %d out of range", path, n)
+			}
+			value = arr[n]
+		}
+	}
+
+	return value, nil
+}
+
+// jsonNormalise returns v as it would be decoded by encoding/json, so it can be compared
+// with a decoded document, e.g. an int becomes a float64.
+func jsonNormalise(v any) (any, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalised any
+	err = json.Unmarshal(raw, &normalised)
+	return normalised, err
+}
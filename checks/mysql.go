@@ -0,0 +1,178 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"database/sql"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/pkg/errors"
+)
+
+const MYSQL Implementation = "mysql"
+
+const mysqlConnectTimeout = 10 * time.Second
+
+type mysqlCheck struct {
+	*BaseCheck
+	host            string
+	port            int
+	dbName          string
+	user            string
+	pass            string
+	tlsConfig       *tls.Config
+	timeout         time.Duration
+	query           string
+	queryArgs       []any
+	requireWritable bool
+}
+
+// MySQLOption configures a mysql check.
+type MySQLOption func(*mysqlCheck)
+
+// WithMySQLTLSConfig connects over TLS using cfg.
+func WithMySQLTLSConfig(cfg *tls.Config) MySQLOption {
+	return func(c *mysqlCheck) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithMySQLTimeout sets the timeout for establishing a connection, defaults to 10 seconds.
+func WithMySQLTimeout(timeout time.Duration) MySQLOption {
+	return func(c *mysqlCheck) {
+		c.timeout = timeout
+	}
+}
+
+// WithMySQLQuery runs query with args after pinging, the check fails unless the query
+// returns at least one row.
+func WithMySQLQuery(query string, args ...any) MySQLOption {
+	return func(c *mysqlCheck) {
+		c.query = query
+		c.queryArgs = args
+	}
+}
+
+// WithMySQLRequireWritable fails the check when the server has read_only or
+// super_read_only enabled.
+func WithMySQLRequireWritable() MySQLOption {
+	return func(c *mysqlCheck) {
+		c.requireWritable = true
+	}
+}
+
+// NewMySQLCheck creates a check against a MySQL or MariaDB server. The read_only and
+// super_read_only server variables are reported in the check's details.
+func NewMySQLCheck(host string, port int, dbName, user, pass string, opts ...MySQLOption) (CheckInterface, error) {
+	check := mysqlCheck{
+		BaseCheck: NewBaseCheck(),
+		host:      host,
+		port:      port,
+		dbName:    dbName,
+		user:      user,
+		pass:      pass,
+		timeout:   mysqlConnectTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	return &check, nil
+}
+
+func (c *mysqlCheck) GetImp() Implementation {
+	return MYSQL
+}
+
+func (c *mysqlCheck) HealthCheck() error {
+	return c.Check(context.Background()).Err
+}
+
+func (c *mysqlCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
+}
+
+func (c *mysqlCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(STARTUP)
+
+	cfg := mysql.NewConfig()
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	cfg.DBName = c.dbName
+	cfg.User = c.user
+	cfg.Passwd = c.pass
+	cfg.TLS = c.tlsConfig
+	cfg.Timeout = c.timeout
+
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error configuring mysql connection"), nil)
+	}
+
+	c.SetStatus(CHECKING)
+
+	conn := sql.OpenDB(connector)
+	defer conn.Close()
+
+	err = conn.PingContext(ctx)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error pinging mysql database"), nil)
+	}
+
+	if c.query != "" {
+		err = validationQuery(ctx, conn, c.query, c.queryArgs...)
+		if err != nil {
+			return c.DoneResult(start, errors.Wrap(err, "mysql validation query failed"), nil)
+		}
+	}
+
+	readOnly, err := mysqlReadOnly(ctx, conn)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error fetching mysql read only status"), nil)
+	}
+
+	details := make(map[string]any, len(readOnly))
+	for name, enabled := range readOnly {
+		details[name] = enabled
+	}
+
+	if c.requireWritable {
+		for name, enabled := range readOnly {
+			if enabled {
+				return c.DoneResult(start, errors.Errorf("mysql server has %s enabled", name), details)
+			}
+		}
+	}
+
+	return c.DoneResult(start, nil, details)
+}
+
+func (c *mysqlCheck) Cleanup() {}
+
+// mysqlReadOnly returns the read_only and super_read_only server variables, MariaDB
+// does not have super_read_only so it is omitted there.
+func mysqlReadOnly(ctx context.Context, conn *sql.DB) (map[string]bool, error) {
+	rows, err := conn.QueryContext(ctx, "SHOW GLOBAL VARIABLES WHERE Variable_name IN ('read_only', 'super_read_only')")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	readOnly := make(map[string]bool)
+	for rows.Next() {
+		var name, value string
+		err = rows.Scan(&name, &value)
+		if err != nil {
+			return nil, err
+		}
+		readOnly[strings.ToLower(name)] = strings.EqualFold(value, "ON") || value == "1"
+	}
+
+	return readOnly, rows.Err()
+}
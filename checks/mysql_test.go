@@ -0,0 +1,158 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestNewMySQLCheck(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewMySQLCheck("localhost", 1234, "wibble", "foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	if aCheck.GetImp() != MYSQL {
+		t.Fatalf("mysqlCheck.GetImp() returned unexpected value: %s", aCheck.GetImp())
+	}
+
+	if aCheck.GetStatus() != STARTUP {
+		t.Fatalf("mysqlCheck.GetStatus() returned unexpected value after initialisation: %s", aCheck.GetStatus())
+	}
+
+	if aCheck.GetLastCheck().Equal(time.Time{}) {
+		t.Fatalf("mysqlCheck.GetLastCheck() returned unexpected value after initialisation: %v", aCheck.GetLastCheck())
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("mysqlCheck.GetError() returned unexpected value after initialisation: %v", aCheck.GetError())
+	}
+}
+
+func TestMySQLHealthCheck(t *testing.T) {
+	const (
+		mysqlPort = 3306
+		mysqlUser = "mysql"
+		mysqlPass = "password"
+		mysqlDb   = "mysql"
+	)
+
+	cntrCtx := context.Background()
+
+	cntrPort, err := nat.NewPort("tcp", fmt.Sprint(mysqlPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mysqlCntr, err := testcontainers.GenericContainer(cntrCtx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "mysql:8",
+			ExposedPorts: []string{fmt.Sprint(mysqlPort)},
+			WaitingFor:   wait.ForAll(wait.ForListeningPort(cntrPort), wait.ForLog("port: 3306  MySQL Community Server")),
+			Env: map[string]string{
+				"MYSQL_ROOT_PASSWORD": mysqlPass,
+				"MYSQL_USER":          mysqlUser,
+				"MYSQL_PASSWORD":      mysqlPass,
+				"MYSQL_DATABASE":      mysqlDb,
+			},
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := mysqlCntr.Terminate(cntrCtx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	cntrHost, err := mysqlCntr.Host(cntrCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cntrPort, err = mysqlCntr.MappedPort(cntrCtx, cntrPort)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck, err := NewMySQLCheck(cntrHost, cntrPort.Int(), mysqlDb, mysqlUser, mysqlPass,
+		WithMySQLQuery("SELECT 1 FROM DUAL WHERE ? = 1", 1),
+		WithMySQLRequireWritable(),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("mysqlCheck.GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if !(aCheck.GetLastCheck().Before(time.Now()) && aCheck.GetLastCheck().After(time.Time{})) {
+		t.Fatalf("mysqlCheck.GetLastCheck() returned unexpected value after calling HealthCheck(): %v", aCheck.GetLastCheck())
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("mysqlCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+
+	res := ToV2(aCheck).Check(cntrCtx)
+	if res.Details["read_only"] != false || res.Details["super_read_only"] != false {
+		t.Fatalf("mysqlCheck.Check() returned unexpected details: %v", res.Details)
+	}
+
+	aCheck, err = NewMySQLCheck(cntrHost, cntrPort.Int(), mysqlDb, mysqlUser, mysqlPass,
+		WithMySQLQuery("SELECT 1 FROM DUAL WHERE ? = 1", 2),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("mysqlCheck.HealthCheck() did not return an error for a validation query returning no rows")
+	}
+}
+
+func TestMySQLHealthCheckNoServer(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewMySQLCheck("localhost", 1234, "wibble", "foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("mysqlCheck.HealthCheck() did not return an error for a failing check")
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("mysqlCheck.GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if !(aCheck.GetLastCheck().Before(time.Now()) && aCheck.GetLastCheck().After(time.Time{})) {
+		t.Fatalf("mysqlCheck.GetLastCheck() returned unexpected value after calling HealthCheck(): %v", aCheck.GetLastCheck())
+	}
+
+	if !errors.Is(aCheck.GetError(), err) {
+		t.Fatalf("mysqlCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+}
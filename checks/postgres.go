@@ -1,88 +1,251 @@
 package checks
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 )
 
 const POSTGRES Implementation = "postgres"
 
 type postgresCheck struct {
-	status    CheckStatus
-	lastCheck time.Time
-	err       error
-	host      string
-	port      int
-	dbName    string
-	user      string
-	pass      string
-	sslMode   string
-}
-
-func NewPostgresCheck(host string, port int, dbName, user, pass, sslMode string) (CheckInterface, error) {
-	check := postgresCheck{
-		status:    STARTUP,
-		lastCheck: time.Unix(0, 0),
-		err:       nil,
-		host:      host,
-		port:      port,
-		dbName:    dbName,
-		user:      user,
-		pass:      pass,
-		sslMode:   sslMode,
+	*BaseCheck
+	dsn            string
+	sslRootCert    string
+	sslCert        string
+	sslKey         string
+	connectTimeout time.Duration
+	query          string
+	args           []any
+	primary        bool
+	maxLag         time.Duration
+}
+
+// PostgresOption configures a postgres check.
+type PostgresOption func(*postgresCheck)
+
+// WithPostgresQuery runs query with args after pinging, the check fails unless the
+// query returns at least one row.
+func WithPostgresQuery(query string, args ...any) PostgresOption {
+	return func(c *postgresCheck) {
+		c.query = query
+		c.args = args
 	}
+}
 
-	return &check, nil
+// WithPostgresRequirePrimary fails the check when the server is in recovery, i.e. it is
+// a read only replica rather than a writable primary.
+func WithPostgresRequirePrimary() PostgresOption {
+	return func(c *postgresCheck) {
+		c.primary = true
+	}
 }
 
-func (c *postgresCheck) GetImp() Implementation {
-	return POSTGRES
+// WithPostgresMaxReplicationLag fails the check when the server is a replica whose last
+// replayed transaction is older than lag. A replica streaming from its primary which has
+// replayed all the WAL it received has no lag, even when the primary is idle. Seeing the
+// WAL receiver's status requires the pg_read_all_stats role, without it the replay time is
+// always used. Primaries always pass.
+func WithPostgresMaxReplicationLag(lag time.Duration) PostgresOption {
+	return func(c *postgresCheck) {
+		c.maxLag = lag
+	}
+}
+
+// WithPostgresSSLRootCert verifies the server certificate against the CA certificates
+// in the PEM file at path.
+func WithPostgresSSLRootCert(path string) PostgresOption {
+	return func(c *postgresCheck) {
+		c.sslRootCert = path
+	}
 }
 
-func (c *postgresCheck) GetStatus() CheckStatus {
-	return c.status
+// WithPostgresSSLCert authenticates with the client certificate and key in the PEM
+// files at certPath and keyPath.
+func WithPostgresSSLCert(certPath, keyPath string) PostgresOption {
+	return func(c *postgresCheck) {
+		c.sslCert = certPath
+		c.sslKey = keyPath
+	}
+}
+
+// WithPostgresConnectTimeout sets the timeout for establishing a connection, which is
+// rounded up to whole seconds.
+func WithPostgresConnectTimeout(timeout time.Duration) PostgresOption {
+	return func(c *postgresCheck) {
+		c.connectTimeout = timeout
+	}
 }
 
-func (c *postgresCheck) GetLastCheck() time.Time {
-	return c.lastCheck
+func NewPostgresCheck(host string, port int, dbName, user, pass, sslMode string, opts ...PostgresOption) (CheckInterface, error) {
+	dsn := postgresDSN(
+		"host", host,
+		"port", strconv.Itoa(port),
+		"user", user,
+		"password", pass,
+		"dbname", dbName,
+		"sslmode", sslMode,
+	)
+
+	return newPostgresCheck(dsn, opts...), nil
+}
+
+// NewPostgresURLCheck creates a postgres check from either a postgres:// URL or a
+// pq style keyword/value connection string. Settings which are missing are read from
+// the standard PG* environment variables, e.g. PGHOST or PGPASSWORD.
+func NewPostgresURLCheck(dsn string, opts ...PostgresOption) (CheckInterface, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		var err error
+		dsn, err = pq.ParseURL(dsn)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidConfig, err.Error())
+		}
+	}
+
+	check := newPostgresCheck(dsn, opts...)
+
+	_, err := pq.NewConnector(check.connStr())
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidConfig, err.Error())
+	}
+
+	return check, nil
+}
+
+// NewPostgresEnvCheck creates a postgres check configured entirely by the standard PG*
+// environment variables, e.g. PGHOST, PGPORT, PGPASSWORD and PGSSLMODE.
+func NewPostgresEnvCheck(opts ...PostgresOption) (CheckInterface, error) {
+	return NewPostgresURLCheck("", opts...)
+}
+
+func newPostgresCheck(dsn string, opts ...PostgresOption) *postgresCheck {
+	check := postgresCheck{
+		BaseCheck: NewBaseCheck(),
+		dsn:       dsn,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	return &check
 }
 
-func (c *postgresCheck) GetError() error {
-	return c.err
+func (c *postgresCheck) GetImp() Implementation {
+	return POSTGRES
 }
 
 func (c *postgresCheck) HealthCheck() error {
-	c.status = STARTUP
+	return c.Check(context.Background()).Err
+}
 
-	psqlConnStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s", c.host, c.port, c.user, c.pass, c.dbName, c.sslMode)
+func (c *postgresCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
+}
+
+func (c *postgresCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(STARTUP)
 
-	c.status = CHECKING
+	c.SetStatus(CHECKING)
 
-	var conn *sql.DB
-	conn, c.err = sql.Open("postgres", psqlConnStr)
-	if c.err != nil {
-		c.err = errors.Wrap(c.err, "error opening postgres connection")
-		c.lastCheck = time.Now()
-		c.status = DONE
-		return c.err
+	conn, err := sql.Open("postgres", c.connStr())
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error opening postgres connection"), nil)
 	}
 	defer conn.Close()
 
-	c.err = conn.Ping()
-	if c.err != nil {
-		c.err = errors.Wrap(c.err, "error pinging postgres database")
-		c.lastCheck = time.Now()
-		c.status = DONE
-		return c.err
+	err = conn.PingContext(ctx)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error pinging postgres database"), nil)
+	}
+
+	if c.query != "" {
+		err = validationQuery(ctx, conn, c.query, c.args...)
+		if err != nil {
+			return c.DoneResult(start, errors.Wrap(err, "postgres validation query failed"), nil)
+		}
 	}
 
-	c.lastCheck = time.Now()
-	c.status = DONE
-	return c.err
+	if !c.primary && c.maxLag <= 0 {
+		return c.DoneResult(start, nil, nil)
+	}
+
+	var (
+		inRecovery bool
+		lag        sql.NullFloat64
+	)
+	// The replay timestamp does not advance whilst the primary is idle, so a replica which
+	// has caught up with the WAL it received reports no lag. The received position stops at
+	// the last WAL received once disconnected, so this only holds whilst streaming.
+	err = conn.QueryRowContext(ctx, `SELECT pg_is_in_recovery(),
+		CASE WHEN EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming')
+			AND pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()) END`).Scan(&inRecovery, &lag)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error fetching postgres recovery status"), nil)
+	}
+
+	details := map[string]any{"in_recovery": inRecovery}
+
+	if c.primary && inRecovery {
+		return c.DoneResult(start, errors.New("postgres server is in recovery, expected a writable primary"), details)
+	}
+
+	if c.maxLag > 0 && inRecovery {
+		if !lag.Valid {
+			return c.DoneResult(start, errors.New("postgres replica has not replayed any transactions, replication lag is unknown"), details)
+		}
+
+		replicationLag := time.Duration(lag.Float64 * float64(time.Second))
+		details["replication_lag"] = replicationLag.String()
+		if replicationLag > c.maxLag {
+			return c.DoneResult(start, errors.Errorf("postgres replication lag %s exceeds maximum %s", replicationLag, c.maxLag), details)
+		}
+	}
+
+	return c.DoneResult(start, nil, details)
 }
 
 func (c *postgresCheck) Cleanup() {}
+
+// connStr returns the check's connection string with any options appended, later
+// keywords override earlier ones.
+func (c *postgresCheck) connStr() string {
+	var keywords []string
+	if c.sslRootCert != "" {
+		keywords = append(keywords, "sslrootcert", c.sslRootCert)
+	}
+	if c.sslCert != "" {
+		keywords = append(keywords, "sslcert", c.sslCert, "sslkey", c.sslKey)
+	}
+	if c.connectTimeout > 0 {
+		seconds := (c.connectTimeout + time.Second - 1) / time.Second
+		keywords = append(keywords, "connect_timeout", strconv.Itoa(int(seconds)))
+	}
+
+	if len(keywords) == 0 {
+		return c.dsn
+	}
+
+	return strings.TrimSpace(c.dsn + " " + postgresDSN(keywords...))
+}
+
+// postgresDSN builds a keyword/value connection string from alternating keywords and
+// values, quoting each value so that spaces, quotes and backslashes are preserved.
+func postgresDSN(keywordValues ...string) string {
+	quoter := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+
+	pairs := make([]string, 0, len(keywordValues)/2)
+	for i := 0; i+1 < len(keywordValues); i += 2 {
+		pairs = append(pairs, fmt.Sprintf("%s='%s'", keywordValues[i], quoter.Replace(keywordValues[i+1])))
+	}
+
+	return strings.Join(pairs, " ")
+}
@@ -2,71 +2,517 @@ package checks
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"cloud.google.com/go/iam"
 	"cloud.google.com/go/pubsub"
 	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 )
 
 const (
 	PUBSUB Implementation = "pubsub"
 
 	healthCheckTopic = "health-check"
+
+	// pubsubRoundTripTimeout bounds a round trip when no timeout is configured.
+	pubsubRoundTripTimeout = 10 * time.Second
+
+	// pubsubPeekWindow and pubsubPeekLimit bound peeking at a backlog unless configured.
+	pubsubPeekWindow = time.Second
+	pubsubPeekLimit  = 1000
+
+	// pubsubRoundTripAttribute is the message attribute identifying round trip messages.
+	pubsubRoundTripAttribute = "health-check-id"
+)
+
+var (
+	// pubsubTopicPermissions are the permissions tested on each configured topic.
+	pubsubTopicPermissions = []string{"pubsub.topics.publish"}
+	// pubsubSubscriptionPermissions are the permissions tested on each configured subscription.
+	pubsubSubscriptionPermissions = []string{"pubsub.subscriptions.consume"}
 )
 
+// PubsubBacklog is the observed backlog of a subscription.
+type PubsubBacklog struct {
+	// Messages is the number of undelivered or unacknowledged messages.
+	Messages int64
+	// OldestUnacked is the age of the oldest unacknowledged message, zero without a backlog.
+	OldestUnacked time.Duration
+	// Capped is set when the source stopped counting, so Messages is a lower bound.
+	Capped bool
+}
+
+// PubsubBacklogSource observes the backlog of subscriptions, e.g. from Cloud Monitoring's
+// num_undelivered_messages and oldest_unacked_message_age metrics.
+type PubsubBacklogSource interface {
+	Backlog(ctx context.Context, sub *pubsub.Subscription) (PubsubBacklog, error)
+}
+
+type pubsubBacklog struct {
+	subscription string
+	maxMessages  int64
+	maxAge       time.Duration
+}
+
 type pubsubCheck struct {
-	status    CheckStatus
-	lastCheck time.Time
-	err       error
-	projectId string
+	*BaseCheck
+	projectId        string
+	topics           []string
+	subscriptions    []string
+	roundTripTopic   string
+	roundTripSub     string
+	roundTripTimeout time.Duration
+	clientOpts       []option.ClientOption
+	backlogs         []pubsubBacklog
+	backlogSource    PubsubBacklogSource
+
+	clientMtx   sync.Mutex
+	client      *pubsub.Client
+	ownedClient bool
+}
+
+// PubsubOption configures a pubsub check.
+type PubsubOption func(*pubsubCheck)
+
+// WithPubsubTopics fails the check unless each of topics exists and can be published to.
+func WithPubsubTopics(topics ...string) PubsubOption {
+	return func(c *pubsubCheck) {
+		c.topics = append(c.topics, topics...)
+	}
+}
+
+// WithPubsubSubscriptions fails the check unless each of subscriptions exists and can
+// be consumed from.
+func WithPubsubSubscriptions(subscriptions ...string) PubsubOption {
+	return func(c *pubsubCheck) {
+		c.subscriptions = append(c.subscriptions, subscriptions...)
+	}
+}
+
+// WithPubsubRoundTrip publishes a uniquely tagged message to the "health-check" topic
+// and receives it on subscription, which must be dedicated to health checks, within
+// timeout, defaults to 10 seconds. The round trip latency is reported in the check's
+// details. Tagged messages abandoned by other checks are acknowledged once they are
+// older than timeout, others are nacked for redelivery.
+func WithPubsubRoundTrip(subscription string, timeout time.Duration) PubsubOption {
+	return func(c *pubsubCheck) {
+		if c.roundTripTopic == "" {
+			c.roundTripTopic = healthCheckTopic
+		}
+		c.roundTripSub = subscription
+		c.roundTripTimeout = timeout
+	}
+}
+
+// WithPubsubRoundTripTopic publishes round trip messages to topic rather than the
+// "health-check" topic.
+func WithPubsubRoundTripTopic(topic string) PubsubOption {
+	return func(c *pubsubCheck) {
+		c.roundTripTopic = topic
+	}
+}
+
+// WithPubsubClientOptions creates the check's client with opts, e.g. to impersonate a
+// service account. They are ignored by checks using an existing client.
+func WithPubsubClientOptions(opts ...option.ClientOption) PubsubOption {
+	return func(c *pubsubCheck) {
+		c.clientOpts = append(c.clientOpts, opts...)
+	}
+}
+
+// WithPubsubCredentialsFile authenticates with the service account or refresh token
+// JSON credentials file at path.
+func WithPubsubCredentialsFile(path string) PubsubOption {
+	return WithPubsubClientOptions(option.WithCredentialsFile(path))
+}
+
+// WithPubsubEndpoint connects to endpoint rather than the default pubsub endpoint.
+func WithPubsubEndpoint(endpoint string) PubsubOption {
+	return WithPubsubClientOptions(option.WithEndpoint(endpoint))
+}
+
+// WithPubsubEmulator connects to the emulator at host, without TLS or authentication,
+// as PUBSUB_EMULATOR_HOST does for every client in the process.
+func WithPubsubEmulator(host string) PubsubOption {
+	return WithPubsubClientOptions(pubsubEmulatorOptions(host)...)
+}
+
+// PubsubPeekBacklogSource observes a backlog by pulling messages for Window, defaults to
+// 1 second, up to Limit messages, defaults to 1000, and then nacking them so they are
+// redelivered to their consumers. Each peek counts as a delivery attempt, which can push
+// messages to a dead letter topic, and holds back delivery for Window. It is only suited
+// to the emulator and test environments, never production subscriptions.
+type PubsubPeekBacklogSource struct {
+	Window time.Duration
+	Limit  int
+}
+
+func (s PubsubPeekBacklogSource) Backlog(ctx context.Context, sub *pubsub.Subscription) (PubsubBacklog, error) {
+	window := s.Window
+	if window <= 0 {
+		window = pubsubPeekWindow
+	}
+	limit := s.limit()
+
+	ctx, cancel := context.WithTimeout(ctx, window)
+	defer cancel()
+
+	// Flow control stops delivering messages to the peek once it holds limit of them.
+	sub.ReceiveSettings.NumGoroutines = 1
+	sub.ReceiveSettings.MaxOutstandingMessages = limit
+
+	var (
+		mtx    sync.Mutex
+		seen   = make(map[string]struct{})
+		oldest time.Time
+	)
+	err := sub.Receive(ctx, func(_ context.Context, msg *pubsub.Message) {
+		// Nacking is deferred until the peek ends, as nacking straight away would race
+		// the client extending the message's lease on receipt, and every message would
+		// be redelivered to the peek.
+		defer msg.Nack()
+
+		mtx.Lock()
+		seen[msg.ID] = struct{}{}
+		if oldest.IsZero() || msg.PublishTime.Before(oldest) {
+			oldest = msg.PublishTime
+		}
+		if len(seen) >= limit {
+			cancel()
+		}
+		mtx.Unlock()
+
+		<-ctx.Done()
+	})
+	if err != nil {
+		return PubsubBacklog{}, err
+	}
+
+	backlog := PubsubBacklog{Messages: int64(len(seen)), Capped: len(seen) >= limit}
+	if !oldest.IsZero() {
+		backlog.OldestUnacked = time.Since(oldest)
+	}
+
+	return backlog, nil
+}
+
+func (s PubsubPeekBacklogSource) limit() int {
+	if s.Limit <= 0 {
+		return pubsubPeekLimit
+	}
+	return s.Limit
+}
+
+func pubsubEmulatorOptions(host string) []option.ClientOption {
+	return []option.ClientOption{
+		option.WithEndpoint(host),
+		option.WithGRPCDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())),
+		option.WithoutAuthentication(),
+		option.WithTelemetryDisabled(),
+	}
+}
+
+// WithPubsubBacklog fails the check when subscription's backlog exceeds maxMessages or
+// its oldest unacknowledged message is older than maxAge, a zero maximum is unbounded.
+// The backlog is reported in the check's details, it is observed by the source given
+// with WithPubsubBacklogSource, which is required.
+func WithPubsubBacklog(subscription string, maxMessages int64, maxAge time.Duration) PubsubOption {
+	return func(c *pubsubCheck) {
+		c.backlogs = append(c.backlogs, pubsubBacklog{subscription: subscription, maxMessages: maxMessages, maxAge: maxAge})
+	}
+}
+
+// WithPubsubBacklogSource observes subscription backlogs with source.
+func WithPubsubBacklogSource(source PubsubBacklogSource) PubsubOption {
+	return func(c *pubsubCheck) {
+		c.backlogSource = source
+	}
+}
+
+// NewPubsubCheck creates a pubsub check. The client is created by the first check and
+// kept until Cleanup.
+func NewPubsubCheck(projectID string, opts ...PubsubOption) (CheckInterface, error) {
+	check := pubsubCheck{
+		BaseCheck:   NewBaseCheck(),
+		projectId:   projectID,
+		ownedClient: true,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	err := check.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return &check, nil
 }
 
-func NewPubsubCheck(projectID string) (CheckInterface, error) {
+// NewPubsubClientCheck creates a pubsub check using an application's existing client
+// and its project. The client is not closed by Cleanup.
+func NewPubsubClientCheck(client *pubsub.Client, opts ...PubsubOption) (CheckInterface, error) {
+	if client == nil {
+		return nil, errors.Wrap(ErrInvalidConfig, "pubsub check requires a client")
+	}
+
 	check := pubsubCheck{
-		status:    STARTUP,
-		lastCheck: time.Unix(0, 0),
-		err:       nil,
-		projectId: projectID,
+		BaseCheck: NewBaseCheck(),
+		projectId: client.Project(),
+		client:    client,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	err := check.validate()
+	if err != nil {
+		return nil, err
 	}
 
 	return &check, nil
 }
 
+// validate returns an error wrapping ErrInvalidConfig if the check's options conflict.
+func (c *pubsubCheck) validate() error {
+	if len(c.backlogs) > 0 && c.backlogSource == nil {
+		return errors.Wrap(ErrInvalidConfig, "pubsub backlog check requires a backlog source")
+	}
+
+	if peek, ok := c.backlogSource.(PubsubPeekBacklogSource); ok {
+		for _, backlog := range c.backlogs {
+			if backlog.maxMessages >= int64(peek.limit()) {
+				return errors.Wrapf(ErrInvalidConfig, "pubsub backlog maximum %d for subscription %s is not below the peek limit %d", backlog.maxMessages, backlog.subscription, peek.limit())
+			}
+		}
+	}
+
+	return nil
+}
+
 func (c *pubsubCheck) GetImp() Implementation {
 	return PUBSUB
 }
 
-func (c *pubsubCheck) GetStatus() CheckStatus {
-	return c.status
+func (c *pubsubCheck) HealthCheck() error {
+	return c.Check(context.Background()).Err
 }
 
-func (c *pubsubCheck) GetLastCheck() time.Time {
-	return c.lastCheck
+func (c *pubsubCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
 }
 
-func (c *pubsubCheck) GetError() error {
-	return c.err
+func (c *pubsubCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(STARTUP)
+
+	c.SetStatus(CHECKING)
+
+	client, err := c.getClient(ctx)
+	if err != nil {
+		return c.DoneResult(start, err, nil)
+	}
+
+	for _, id := range c.topics {
+		topic := client.Topic(id)
+		err = pubsubResource(ctx, "topic", topic.String(), topic.Exists, topic.IAM(), pubsubTopicPermissions)
+		if err != nil {
+			return c.DoneResult(start, err, nil)
+		}
+	}
+
+	for _, id := range c.subscriptions {
+		sub := client.Subscription(id)
+		err = pubsubResource(ctx, "subscription", sub.String(), sub.Exists, sub.IAM(), pubsubSubscriptionPermissions)
+		if err != nil {
+			return c.DoneResult(start, err, nil)
+		}
+	}
+
+	details := make(map[string]any)
+
+	if len(c.backlogs) > 0 {
+		backlogs := make(map[string]any, len(c.backlogs))
+		details["backlog"] = backlogs
+
+		for _, backlog := range c.backlogs {
+			err = c.inspectBacklog(ctx, client, backlog, backlogs)
+			if err != nil {
+				return c.DoneResult(start, err, details)
+			}
+		}
+	}
+
+	if c.roundTripSub != "" {
+		latency, err := c.publishReceive(ctx, client)
+		if err != nil {
+			return c.DoneResult(start, err, details)
+		}
+		details["round_trip_latency"] = latency.String()
+	}
+
+	return c.DoneResult(start, nil, details)
 }
 
-func (c *pubsubCheck) HealthCheck() error {
-	c.status = STARTUP
+func (c *pubsubCheck) Cleanup() {
+	c.clientMtx.Lock()
+	defer c.clientMtx.Unlock()
+
+	if c.client != nil && c.ownedClient {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+// getClient returns the check's client, creating it if this is the first check or the
+// previous attempt failed.
+func (c *pubsubCheck) getClient(ctx context.Context) (*pubsub.Client, error) {
+	c.clientMtx.Lock()
+	defer c.clientMtx.Unlock()
 
-	c.status = CHECKING
+	if c.client != nil {
+		return c.client, nil
+	}
 
-	// Creating pubsub client
-	var client *pubsub.Client
-	client, c.err = pubsub.NewClient(context.Background(), c.projectId)
-	if c.err != nil {
-		c.err = errors.Wrap(c.err, "error creating new pubsub client")
-		c.lastCheck = time.Now()
-		c.status = DONE
-		return c.err
+	// The client outlives this check, so must not be bound to its cancellation.
+	client, err := pubsub.NewClient(context.WithoutCancel(ctx), c.projectId, c.clientOpts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "error creating new pubsub client")
 	}
-	defer client.Close()
 
-	c.lastCheck = time.Now()
-	c.status = DONE
-	return c.err
+	c.client = client
+	return client, nil
 }
 
-func (c *pubsubCheck) Cleanup() {}
+// publishReceive publishes a uniquely tagged message to the round trip topic and
+// receives it on the round trip subscription, returning the time taken.
+func (c *pubsubCheck) publishReceive(ctx context.Context, client *pubsub.Client) (time.Duration, error) {
+	timeout := c.roundTripTimeout
+	if timeout <= 0 {
+		timeout = pubsubRoundTripTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	id := make([]byte, 16)
+	_, err := rand.Read(id)
+	if err != nil {
+		return 0, errors.Wrap(err, "error generating pubsub message id")
+	}
+	messageId := hex.EncodeToString(id)
+
+	topic := client.Topic(c.roundTripTopic)
+	defer topic.Stop()
+
+	published := time.Now()
+
+	_, err = topic.Publish(ctx, &pubsub.Message{
+		Data:       []byte(messageId),
+		Attributes: map[string]string{pubsubRoundTripAttribute: messageId},
+	}).Get(ctx)
+	if err != nil {
+		return 0, errors.Wrapf(err, "error publishing to pubsub topic %s", topic)
+	}
+
+	sub := client.Subscription(c.roundTripSub)
+	sub.ReceiveSettings.NumGoroutines = 1
+
+	// Messages may be handled concurrently, the latency is only set by the first delivery
+	// of the tagged message.
+	var latency atomic.Int64
+	receiveCtx, received := context.WithCancel(ctx)
+	defer received()
+
+	err = sub.Receive(receiveCtx, func(_ context.Context, msg *pubsub.Message) {
+		switch {
+		case msg.Attributes[pubsubRoundTripAttribute] == messageId:
+			msg.Ack()
+			latency.CompareAndSwap(0, int64(time.Since(published)))
+			received()
+		case msg.PublishTime.Before(time.Now().Add(-timeout)):
+			msg.Ack()
+		default:
+			msg.Nack()
+		}
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "error receiving from pubsub subscription %s", sub)
+	}
+
+	if latency.Load() == 0 {
+		err = ctx.Err()
+		if err == nil {
+			err = errors.New("receive stopped")
+		}
+		return 0, errors.Wrapf(err, "error waiting for message from pubsub subscription %s", sub)
+	}
+
+	return time.Duration(latency.Load()), nil
+}
+
+// inspectBacklog observes backlog's subscription, recording it in backlogs and returning
+// an error when it exceeds the backlog's maximums.
+func (c *pubsubCheck) inspectBacklog(ctx context.Context, client *pubsub.Client, backlog pubsubBacklog, backlogs map[string]any) error {
+	sub := client.Subscription(backlog.subscription)
+	observed, err := c.backlogSource.Backlog(ctx, sub)
+	if err != nil {
+		return errors.Wrapf(err, "error observing backlog of pubsub subscription %s", sub)
+	}
+
+	backlogs[backlog.subscription] = map[string]any{
+		"messages":           observed.Messages,
+		"oldest_unacked_age": observed.OldestUnacked.String(),
+		"messages_capped":    observed.Capped,
+	}
+
+	if backlog.maxMessages > 0 && observed.Messages > backlog.maxMessages {
+		return errors.Errorf("pubsub subscription %s has %d undelivered messages, maximum %d", sub, observed.Messages, backlog.maxMessages)
+	}
+	if backlog.maxAge > 0 && observed.OldestUnacked > backlog.maxAge {
+		return errors.Errorf("pubsub subscription %s oldest unacked message is %s old, maximum %s", sub, observed.OldestUnacked, backlog.maxAge)
+	}
+
+	return nil
+}
+
+// pubsubResource returns an error naming the resource unless it exists and the caller
+// has permissions on it. Servers which do not implement IAM, such as the emulator,
+// are assumed to grant every permission.
+func pubsubResource(ctx context.Context, kind, name string, exists func(context.Context) (bool, error), handle *iam.Handle, permissions []string) error {
+	ok, err := exists(ctx)
+	if err != nil {
+		return errors.Wrapf(err, "error checking pubsub %s %s exists", kind, name)
+	}
+	if !ok {
+		return errors.Errorf("pubsub %s %s does not exist", kind, name)
+	}
+
+	granted, err := handle.TestPermissions(ctx, permissions)
+	if status.Code(err) == codes.Unimplemented {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "error testing permissions on pubsub %s %s", kind, name)
+	}
+
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			return errors.Errorf("missing permission %s on pubsub %s %s", permission, kind, name)
+		}
+	}
+
+	return nil
+}
@@ -3,13 +3,20 @@ package checks
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
+	"cloud.google.com/go/iam/apiv1/iampb"
+	"cloud.google.com/go/pubsub"
+	"cloud.google.com/go/pubsub/pstest"
 	"github.com/docker/go-connections/nat"
 	"github.com/pkg/errors"
 	"github.com/testcontainers/testcontainers-go"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 func TestNewPubsubCheck(t *testing.T) {
@@ -56,9 +63,6 @@ func TestPubsubHealthCheck(t *testing.T) {
 			Image:        "gcr.io/google.com/cloudsdktool/cloud-sdk:367.0.0-emulators",
 			ExposedPorts: []string{fmt.Sprint(pubsubPort)},
 			WaitingFor:   wait.ForAll(wait.ForLog(fmt.Sprintf("Server started, listening on %d", pubsubPort))),
-			Env: map[string]string{
-				"PUBSUB_PROJECT1": fmt.Sprintf("%s,%s", pubsubProjectID, "health-check:health-check-sub"),
-			},
 			Cmd: []string{
 				"/bin/sh",
 				"-c",
@@ -88,7 +92,28 @@ func TestPubsubHealthCheck(t *testing.T) {
 	t.Setenv("PUBSUB_EMULATOR_HOST", fmt.Sprintf("%s:%d", cntrHost, cntrPort.Int()))
 	// t.Setenv("PUBSUB_EMULATOR", "localhost:8681")
 
-	aCheck, err := NewPubsubCheck(pubsubProjectID)
+	// The emulator starts without any resources, the check expects them to exist.
+	client, err := pubsub.NewClient(cntrCtx, pubsubProjectID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	topic, err := client.CreateTopic(cntrCtx, healthCheckTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreateSubscription(cntrCtx, "health-check-sub", pubsub.SubscriptionConfig{Topic: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck, err := NewPubsubCheck(pubsubProjectID,
+		WithPubsubTopics(healthCheckTopic),
+		WithPubsubSubscriptions("health-check-sub"),
+		WithPubsubRoundTrip("health-check-sub", 10*time.Second),
+	)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -138,3 +163,277 @@ func TestPubsubHealthCheckNoServer(t *testing.T) {
 		t.Fatalf("pubsubCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
 	}
 }
+
+// testIAMServer grants the permissions listed for each resource, resources which are
+// not listed respond as the emulator does, that IAM is not implemented.
+type testIAMServer struct {
+	iampb.UnimplementedIAMPolicyServer
+	granted map[string][]string
+}
+
+func (s *testIAMServer) TestIamPermissions(_ context.Context, req *iampb.TestIamPermissionsRequest) (*iampb.TestIamPermissionsResponse, error) {
+	granted, ok := s.granted[req.Resource]
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "iam not implemented")
+	}
+	return &iampb.TestIamPermissionsResponse{Permissions: granted}, nil
+}
+
+// testPubsubServer starts an in-memory pubsub server, with the topic health-check and
+// subscription health-check-sub, checks connect to it with WithPubsubEmulator.
+func testPubsubServer(t *testing.T, projectID string, iamServer *testIAMServer) *pstest.Server {
+	t.Helper()
+
+	srv := pstest.NewServerWithCallback(0, func(s *grpc.Server) {
+		iampb.RegisterIAMPolicyServer(s, iamServer)
+	})
+	t.Cleanup(func() { srv.Close() })
+
+	ctx := context.Background()
+	client, err := pubsub.NewClient(ctx, projectID, pubsubEmulatorOptions(srv.Addr)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	topic, err := client.CreateTopic(ctx, healthCheckTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.CreateSubscription(ctx, "health-check-sub", pubsub.SubscriptionConfig{Topic: topic})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return srv
+}
+
+func TestPubsubHealthCheckResources(t *testing.T) {
+	t.Parallel()
+
+	const pubsubProjectID = "wibble-foo"
+
+	srv := testPubsubServer(t, pubsubProjectID, &testIAMServer{
+		granted: map[string][]string{
+			"projects/wibble-foo/subscriptions/health-check-sub": nil,
+		},
+	})
+
+	aCheck, err := NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr), WithPubsubTopics(healthCheckTopic))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		opt      PubsubOption
+		expected string
+	}{
+		{"missing topic", WithPubsubTopics("wibble"), "pubsub topic projects/wibble-foo/topics/wibble does not exist"},
+		{"missing subscription", WithPubsubSubscriptions("wibble"), "pubsub subscription projects/wibble-foo/subscriptions/wibble does not exist"},
+		{"missing permission", WithPubsubSubscriptions("health-check-sub"), "missing permission pubsub.subscriptions.consume on pubsub subscription projects/wibble-foo/subscriptions/health-check-sub"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aCheck, err := NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr), tt.opt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer aCheck.Cleanup()
+
+			err = aCheck.HealthCheck()
+			if err == nil || err.Error() != tt.expected {
+				t.Fatalf("pubsubCheck.HealthCheck() returned unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestPubsubHealthCheckRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	const pubsubProjectID = "wibble-foo"
+
+	srv := testPubsubServer(t, pubsubProjectID, &testIAMServer{})
+
+	aCheck, err := NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr), WithPubsubRoundTrip("health-check-sub", 5*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	// A message left behind by another check is requeued rather than acknowledged.
+	srv.Publish("projects/wibble-foo/topics/health-check", []byte("wibble"), map[string]string{pubsubRoundTripAttribute: "wibble"})
+
+	var client *pubsub.Client
+	for i := 0; i < 2; i++ {
+		res := ToV2(aCheck).Check(context.Background())
+		if res.Err != nil {
+			t.Fatal(res.Err)
+		}
+
+		if _, ok := res.Details["round_trip_latency"]; !ok {
+			t.Fatalf("pubsubCheck.Check() returned unexpected details: %v", res.Details)
+		}
+
+		if client != nil && client != aCheck.(*pubsubCheck).client {
+			t.Fatalf("pubsubCheck.Check() did not reuse its client")
+		}
+		client = aCheck.(*pubsubCheck).client
+	}
+
+	for _, msg := range srv.Messages() {
+		if msg.Attributes[pubsubRoundTripAttribute] == "wibble" && msg.Acks != 0 {
+			t.Fatalf("pubsubCheck.Check() acknowledged another check's message")
+		}
+	}
+
+	_, err = client.CreateTopic(context.Background(), "wibble")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck, err = NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr), WithPubsubRoundTrip("health-check-sub", 100*time.Millisecond), WithPubsubRoundTripTopic("wibble"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("pubsubCheck.HealthCheck() returned unexpected error for a message which is never received: %v", err)
+	}
+}
+
+func TestPubsubClientCheck(t *testing.T) {
+	t.Parallel()
+
+	const pubsubProjectID = "wibble-foo"
+
+	srv := testPubsubServer(t, pubsubProjectID, &testIAMServer{})
+
+	client, err := pubsub.NewClient(context.Background(), pubsubProjectID, pubsubEmulatorOptions(srv.Addr)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	aCheck, err := NewPubsubClientCheck(client, WithPubsubTopics(healthCheckTopic))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck.Cleanup()
+
+	_, err = client.Topic(healthCheckTopic).Exists(context.Background())
+	if err != nil {
+		t.Fatalf("pubsubCheck.Cleanup() closed a client it does not own: %v", err)
+	}
+
+	_, err = NewPubsubClientCheck(nil)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("NewPubsubClientCheck() returned unexpected error for a nil client: %v", err)
+	}
+}
+
+// testBacklogSource reports the same backlog for every subscription.
+type testBacklogSource PubsubBacklog
+
+func (s testBacklogSource) Backlog(context.Context, *pubsub.Subscription) (PubsubBacklog, error) {
+	return PubsubBacklog(s), nil
+}
+
+func TestPubsubHealthCheckBacklog(t *testing.T) {
+	t.Parallel()
+
+	const pubsubProjectID = "wibble-foo"
+
+	srv := testPubsubServer(t, pubsubProjectID, &testIAMServer{})
+	for i := 0; i < 3; i++ {
+		srv.Publish("projects/wibble-foo/topics/health-check", []byte("wibble"), nil)
+	}
+
+	_, err := NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr), WithPubsubBacklog("health-check-sub", 5, time.Minute))
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("NewPubsubCheck() returned unexpected error for a backlog without a source: %v", err)
+	}
+
+	_, err = NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr),
+		WithPubsubBacklog("health-check-sub", 1000, time.Minute),
+		WithPubsubBacklogSource(PubsubPeekBacklogSource{}),
+	)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("NewPubsubCheck() returned unexpected error for a maximum the peek cannot reach: %v", err)
+	}
+
+	aCheck, err := NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr),
+		WithPubsubBacklog("health-check-sub", 5, time.Minute),
+		WithPubsubBacklogSource(PubsubPeekBacklogSource{Window: time.Second}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	res := ToV2(aCheck).Check(context.Background())
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	backlog, ok := res.Details["backlog"].(map[string]any)["health-check-sub"].(map[string]any)
+	if !ok || backlog["messages"] != int64(3) || backlog["messages_capped"] != false {
+		t.Fatalf("pubsubCheck.Check() returned unexpected details: %v", res.Details)
+	}
+
+	for _, msg := range srv.Messages() {
+		if msg.Acks != 0 {
+			t.Fatalf("pubsubCheck.Check() acknowledged a message whilst peeking at the backlog")
+		}
+	}
+
+	aCheck, err = NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr),
+		WithPubsubBacklog("health-check-sub", 1, 0),
+		WithPubsubBacklogSource(PubsubPeekBacklogSource{Window: time.Second, Limit: 2}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	res = ToV2(aCheck).Check(context.Background())
+	if res.Err == nil {
+		t.Fatalf("pubsubCheck.Check() did not return an error for a backlog exceeding its maximum")
+	}
+
+	backlog, ok = res.Details["backlog"].(map[string]any)["health-check-sub"].(map[string]any)
+	if !ok || backlog["messages"] != int64(2) || backlog["messages_capped"] != true {
+		t.Fatalf("pubsubCheck.Check() returned unexpected details for a capped peek: %v", res.Details)
+	}
+
+	aCheck, err = NewPubsubCheck(pubsubProjectID, WithPubsubEmulator(srv.Addr),
+		WithPubsubBacklog("health-check-sub", 0, time.Minute),
+		WithPubsubBacklogSource(testBacklogSource{Messages: 1, OldestUnacked: time.Hour}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil || !strings.Contains(err.Error(), "oldest unacked message is 1h0m0s old") {
+		t.Fatalf("pubsubCheck.HealthCheck() returned unexpected error for an old backlog: %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+)
+
+const REDIS Implementation = "redis"
+
+const (
+	// RedisMaster is the role reported by a redis primary.
+	RedisMaster = "master"
+	// RedisReplica is the role reported by a redis replica.
+	RedisReplica = "slave"
+)
+
+type redisCheck struct {
+	*BaseCheck
+	host           string
+	port           int
+	user           string
+	pass           string
+	db             int
+	tlsConfig      *tls.Config
+	role           string
+	maxMemoryRatio float64
+}
+
+// RedisOption configures a redis check.
+type RedisOption func(*redisCheck)
+
+// WithRedisAuth sets the credentials used to authenticate, user may be empty to use
+// the default user with password authentication.
+func WithRedisAuth(user, pass string) RedisOption {
+	return func(c *redisCheck) {
+		c.user = user
+		c.pass = pass
+	}
+}
+
+// WithRedisDB sets the database index selected after connecting.
+func WithRedisDB(db int) RedisOption {
+	return func(c *redisCheck) {
+		c.db = db
+	}
+}
+
+// WithRedisTLSConfig connects over TLS using cfg.
+func WithRedisTLSConfig(cfg *tls.Config) RedisOption {
+	return func(c *redisCheck) {
+		c.tlsConfig = cfg
+	}
+}
+
+// WithRedisRole fails the check unless the server's replication role is role,
+// RedisMaster or RedisReplica. Replicas must also have an up link to their master.
+func WithRedisRole(role string) RedisOption {
+	return func(c *redisCheck) {
+		c.role = role
+	}
+}
+
+// WithRedisMaxMemoryUsage fails the check when used memory exceeds ratio of the
+// server's maxmemory, e.g. 0.9. Servers without a maxmemory limit always pass.
+func WithRedisMaxMemoryUsage(ratio float64) RedisOption {
+	return func(c *redisCheck) {
+		c.maxMemoryRatio = ratio
+	}
+}
+
+func NewRedisCheck(host string, port int, opts ...RedisOption) (CheckInterface, error) {
+	check := redisCheck{
+		BaseCheck: NewBaseCheck(),
+		host:      host,
+		port:      port,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	return &check, nil
+}
+
+func (c *redisCheck) GetImp() Implementation {
+	return REDIS
+}
+
+func (c *redisCheck) HealthCheck() error {
+	return c.Check(context.Background()).Err
+}
+
+func (c *redisCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
+}
+
+func (c *redisCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(STARTUP)
+
+	client := redis.NewClient(&redis.Options{
+		Addr:       net.JoinHostPort(c.host, strconv.Itoa(c.port)),
+		Username:   c.user,
+		Password:   c.pass,
+		DB:         c.db,
+		TLSConfig:  c.tlsConfig,
+		MaxRetries: -1,
+	})
+	defer client.Close()
+
+	c.SetStatus(CHECKING)
+
+	err := client.Ping(ctx).Err()
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error pinging redis"), nil)
+	}
+
+	details := make(map[string]any)
+
+	if c.role != "" {
+		info, err := client.Info(ctx, "replication").Result()
+		if err != nil {
+			return c.DoneResult(start, errors.Wrap(err, "error fetching redis replication info"), details)
+		}
+
+		replication := parseRedisInfo(info)
+		details["role"] = replication["role"]
+
+		if replication["role"] != c.role {
+			return c.DoneResult(start, errors.Errorf("redis role is %q, expected %q", replication["role"], c.role), details)
+		}
+
+		if c.role == RedisReplica {
+			details["master_link_status"] = replication["master_link_status"]
+			if replication["master_link_status"] != "up" {
+				return c.DoneResult(start, errors.Errorf("redis replica link to master is %q", replication["master_link_status"]), details)
+			}
+		}
+	}
+
+	if c.maxMemoryRatio > 0 {
+		info, err := client.Info(ctx, "memory").Result()
+		if err != nil {
+			return c.DoneResult(start, errors.Wrap(err, "error fetching redis memory info"), details)
+		}
+
+		memory := parseRedisInfo(info)
+		used, _ := strconv.ParseInt(memory["used_memory"], 10, 64)
+		limit, _ := strconv.ParseInt(memory["maxmemory"], 10, 64)
+		details["used_memory"] = used
+		details["maxmemory"] = limit
+
+		if limit > 0 && float64(used) > c.maxMemoryRatio*float64(limit) {
+			return c.DoneResult(start, errors.Errorf("redis memory usage %d bytes exceeds %.0f%% of maxmemory %d bytes", used, c.maxMemoryRatio*100, limit), details)
+		}
+	}
+
+	return c.DoneResult(start, nil, details)
+}
+
+func (c *redisCheck) Cleanup() {}
+
+// parseRedisInfo parses the output of the redis INFO command into its fields.
+func parseRedisInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if ok {
+			fields[key] = value
+		}
+	}
+	return fields
+}
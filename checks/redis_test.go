@@ -0,0 +1,172 @@
+package checks
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/pkg/errors"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+func TestNewRedisCheck(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewRedisCheck("wibble", 1234, WithRedisAuth("foo", "bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	if aCheck.GetImp() != REDIS {
+		t.Fatalf("redisCheck.GetImp() returned unexpected value: %s", aCheck.GetImp())
+	}
+
+	if aCheck.GetStatus() != STARTUP {
+		t.Fatalf("redisCheck.GetStatus() returned unexpected value after initialisation: %s", aCheck.GetStatus())
+	}
+
+	if aCheck.GetLastCheck().Equal(time.Time{}) {
+		t.Fatalf("redisCheck.GetLastCheck() returned unexpected value after initialisation: %v", aCheck.GetLastCheck())
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("redisCheck.GetError() returned unexpected value after initialisation: %v", aCheck.GetError())
+	}
+}
+
+func TestParseRedisInfo(t *testing.T) {
+	t.Parallel()
+
+	fields := parseRedisInfo("# Replication\r\nrole:slave\r\nmaster_link_status:up\r\n\r\n# Memory\r\nused_memory:1024\r\n")
+
+	if fields["role"] != RedisReplica || fields["master_link_status"] != "up" || fields["used_memory"] != "1024" {
+		t.Fatalf("parseRedisInfo() returned unexpected fields: %v", fields)
+	}
+}
+
+func TestRedisHealthCheck(t *testing.T) {
+	const (
+		redisPort = 6379
+		redisPass = "password"
+	)
+
+	cntrCtx := context.Background()
+
+	cntrPort, err := nat.NewPort("tcp", fmt.Sprint(redisPort))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redisCntr, err := testcontainers.GenericContainer(cntrCtx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        "redis:alpine",
+			ExposedPorts: []string{fmt.Sprint(redisPort)},
+			WaitingFor:   wait.ForAll(wait.ForListeningPort(cntrPort), wait.ForLog("Ready to accept connections")),
+			Cmd:          []string{"redis-server", "--requirepass", redisPass, "--maxmemory", "100mb"},
+		},
+		Started: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := redisCntr.Terminate(cntrCtx); err != nil {
+			t.Fatal(err)
+		}
+	}()
+
+	cntrHost, err := redisCntr.Host(cntrCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cntrPort, err = redisCntr.MappedPort(cntrCtx, cntrPort)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck, err := NewRedisCheck(cntrHost, cntrPort.Int(),
+		WithRedisAuth("", redisPass),
+		WithRedisDB(1),
+		WithRedisRole(RedisMaster),
+		WithRedisMaxMemoryUsage(0.9),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("redisCheck.GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if !(aCheck.GetLastCheck().Before(time.Now()) && aCheck.GetLastCheck().After(time.Time{})) {
+		t.Fatalf("redisCheck.GetLastCheck() returned unexpected value after calling HealthCheck(): %v", aCheck.GetLastCheck())
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("redisCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+
+	res := ToV2(aCheck).Check(cntrCtx)
+	if res.Details["role"] != RedisMaster || res.Details["maxmemory"] != int64(100*1024*1024) {
+		t.Fatalf("redisCheck.Check() returned unexpected details: %v", res.Details)
+	}
+
+	aCheck, err = NewRedisCheck(cntrHost, cntrPort.Int(), WithRedisAuth("", redisPass), WithRedisRole(RedisReplica))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("redisCheck.HealthCheck() did not return an error for an unexpected role")
+	}
+
+	aCheck, err = NewRedisCheck(cntrHost, cntrPort.Int(), WithRedisAuth("", "wibble"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("redisCheck.HealthCheck() did not return an error for invalid credentials")
+	}
+}
+
+func TestRedisHealthCheckNoServer(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewRedisCheck("wibble", 1234)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("redisCheck.HealthCheck() did not return an error for a failing check")
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("redisCheck.GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if !(aCheck.GetLastCheck().Before(time.Now()) && aCheck.GetLastCheck().After(time.Time{})) {
+		t.Fatalf("redisCheck.GetLastCheck() returned unexpected value after calling HealthCheck(): %v", aCheck.GetLastCheck())
+	}
+
+	if !errors.Is(aCheck.GetError(), err) {
+		t.Fatalf("redisCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+}
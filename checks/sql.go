@@ -0,0 +1,173 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const SQL Implementation = "sql"
+
+// ErrSQLPoolWait is returned when connections waited on the pool for longer than allowed.
+var ErrSQLPoolWait = errors.New("sql connection pool wait exceeded")
+
+type sqlCheck struct {
+	*BaseCheck
+	db        *sql.DB
+	owned     bool
+	query     string
+	queryArgs []any
+	maxWait   time.Duration
+	waitMtx   sync.Mutex
+	lastWait  time.Duration
+}
+
+// SQLOption configures a sql check.
+type SQLOption func(*sqlCheck)
+
+// WithSQLQuery runs query with args after pinging, the check fails unless the query
+// returns at least one row.
+func WithSQLQuery(query string, args ...any) SQLOption {
+	return func(c *sqlCheck) {
+		c.query = query
+		c.queryArgs = args
+	}
+}
+
+// WithSQLMaxWaitDuration fails the check when the time spent waiting for a pooled
+// connection since the previous check exceeds wait.
+func WithSQLMaxWaitDuration(wait time.Duration) SQLOption {
+	return func(c *sqlCheck) {
+		c.maxWait = wait
+	}
+}
+
+// NewSQLCheck creates a driver agnostic check using an application's existing
+// connection pool. The pool is not closed by Cleanup.
+func NewSQLCheck(db *sql.DB, opts ...SQLOption) (CheckInterface, error) {
+	if db == nil {
+		return nil, errors.Wrap(ErrInvalidConfig, "sql check requires a database")
+	}
+
+	check := sqlCheck{
+		BaseCheck: NewBaseCheck(),
+		db:        db,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	return &check, nil
+}
+
+// NewSQLConnectorCheck creates a driver agnostic check with its own connection pool
+// opened from connector, which is closed by Cleanup.
+func NewSQLConnectorCheck(connector driver.Connector, opts ...SQLOption) (CheckInterface, error) {
+	if connector == nil {
+		return nil, errors.Wrap(ErrInvalidConfig, "sql check requires a connector")
+	}
+
+	check, err := NewSQLCheck(sql.OpenDB(connector), opts...)
+	if err != nil {
+		return nil, err
+	}
+	check.(*sqlCheck).owned = true
+
+	return check, nil
+}
+
+func (c *sqlCheck) GetImp() Implementation {
+	return SQL
+}
+
+func (c *sqlCheck) HealthCheck() error {
+	return c.Check(context.Background()).Err
+}
+
+func (c *sqlCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
+}
+
+func (c *sqlCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(CHECKING)
+
+	err := c.db.PingContext(ctx)
+	if err != nil {
+		return c.DoneResult(start, errors.Wrap(err, "error pinging sql database"), c.stats())
+	}
+
+	if c.query != "" {
+		err = validationQuery(ctx, c.db, c.query, c.queryArgs...)
+		if err != nil {
+			return c.DoneResult(start, errors.Wrap(err, "sql validation query failed"), c.stats())
+		}
+	}
+
+	details := c.stats()
+
+	if c.maxWait > 0 {
+		wait := c.poolWait()
+		details["wait_duration_since_last_check"] = wait.String()
+		if wait > c.maxWait {
+			return c.DoneResult(start, errors.Wrapf(ErrSQLPoolWait, "waited %s for connections, maximum %s", wait, c.maxWait), details)
+		}
+	}
+
+	return c.DoneResult(start, nil, details)
+}
+
+func (c *sqlCheck) Cleanup() {
+	if c.owned {
+		c.db.Close()
+	}
+}
+
+func (c *sqlCheck) stats() map[string]any {
+	stats := c.db.Stats()
+	return map[string]any{
+		"max_open_connections": stats.MaxOpenConnections,
+		"open_connections":     stats.OpenConnections,
+		"in_use":               stats.InUse,
+		"idle":                 stats.Idle,
+		"wait_count":           stats.WaitCount,
+		"wait_duration":        stats.WaitDuration.String(),
+	}
+}
+
+// poolWait returns the time spent waiting for connections since it was last called.
+// The first call measures from when the pool was opened.
+func (c *sqlCheck) poolWait() time.Duration {
+	total := c.db.Stats().WaitDuration
+
+	c.waitMtx.Lock()
+	defer c.waitMtx.Unlock()
+
+	wait := total - c.lastWait
+	c.lastWait = total
+	return wait
+}
+
+// validationQuery runs query with args and returns an error unless it returns at
+// least one row.
+func validationQuery(ctx context.Context, conn *sql.DB, query string, args ...any) error {
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err = rows.Err(); err != nil {
+			return err
+		}
+		return errors.New("query returned no rows")
+	}
+
+	return rows.Close()
+}
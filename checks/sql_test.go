@@ -0,0 +1,218 @@
+package checks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// testConnector is a driver.Connector whose connections ping and query according to
+// its fields, rows is the number of rows every query returns.
+type testConnector struct {
+	pingErr error
+	rows    int
+	opened  atomic.Int32
+}
+
+func (c *testConnector) Connect(context.Context) (driver.Conn, error) {
+	c.opened.Add(1)
+	return &testConn{connector: c}, nil
+}
+
+func (c *testConnector) Driver() driver.Driver {
+	return nil
+}
+
+type testConn struct {
+	connector *testConnector
+}
+
+func (c *testConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (c *testConn) Ping(context.Context) error {
+	return c.connector.pingErr
+}
+
+func (c *testConn) QueryContext(context.Context, string, []driver.NamedValue) (driver.Rows, error) {
+	return &testRows{remaining: c.connector.rows}, nil
+}
+
+type testRows struct {
+	remaining int
+}
+
+func (r *testRows) Columns() []string {
+	return []string{"?column?"}
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.remaining == 0 {
+		return io.EOF
+	}
+	r.remaining--
+	dest[0] = int64(1)
+	return nil
+}
+
+func TestNewSQLCheck(t *testing.T) {
+	t.Parallel()
+
+	db := sql.OpenDB(&testConnector{})
+	defer db.Close()
+
+	aCheck, err := NewSQLCheck(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	if aCheck.GetImp() != SQL {
+		t.Fatalf("sqlCheck.GetImp() returned unexpected value: %s", aCheck.GetImp())
+	}
+
+	if aCheck.GetStatus() != STARTUP {
+		t.Fatalf("sqlCheck.GetStatus() returned unexpected value after initialisation: %s", aCheck.GetStatus())
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("sqlCheck.GetError() returned unexpected value after initialisation: %v", aCheck.GetError())
+	}
+
+	_, err = NewSQLCheck(nil)
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("NewSQLCheck() returned unexpected error for a nil database: %v", err)
+	}
+}
+
+func TestSQLHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	connector := &testConnector{rows: 1}
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	aCheck, err := NewSQLCheck(db, WithSQLQuery("SELECT 1 FROM schema_migrations WHERE version = $1", 42))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		err = aCheck.HealthCheck()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("sqlCheck.GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if connector.opened.Load() != 1 {
+		t.Fatalf("sqlCheck.HealthCheck() did not reuse the pool, opened %d connections", connector.opened.Load())
+	}
+
+	res := ToV2(aCheck).Check(context.Background())
+	if res.Details["open_connections"] != 1 || res.Details["idle"] != 1 || res.Details["in_use"] != 0 {
+		t.Fatalf("sqlCheck.Check() returned unexpected details: %v", res.Details)
+	}
+
+	aCheck.Cleanup()
+	err = db.PingContext(context.Background())
+	if err != nil {
+		t.Fatalf("sqlCheck.Cleanup() closed a database it does not own: %v", err)
+	}
+}
+
+func TestSQLHealthCheckFailing(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewSQLConnectorCheck(&testConnector{pingErr: errors.New("connection refused")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("sqlCheck.HealthCheck() did not return an error for a failing ping")
+	}
+
+	if !errors.Is(aCheck.GetError(), err) {
+		t.Fatalf("sqlCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+
+	aCheck, err = NewSQLConnectorCheck(&testConnector{}, WithSQLQuery("SELECT 1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("sqlCheck.HealthCheck() did not return an error for a validation query returning no rows")
+	}
+}
+
+func TestSQLHealthCheckPoolWait(t *testing.T) {
+	t.Parallel()
+
+	db := sql.OpenDB(&testConnector{})
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	aCheck, err := NewSQLCheck(db, WithSQLMaxWaitDuration(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	ctx := context.Background()
+
+	held, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	waited := make(chan struct{})
+	go func() {
+		defer close(waited)
+		conn, err := db.Conn(ctx)
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	held.Close()
+	<-waited
+
+	err = aCheck.HealthCheck()
+	if !errors.Is(err, ErrSQLPoolWait) {
+		t.Fatalf("sqlCheck.HealthCheck() returned unexpected error after waiting on the pool: %v", err)
+	}
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatalf("sqlCheck.HealthCheck() returned unexpected error without further waits: %v", err)
+	}
+}
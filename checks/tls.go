@@ -0,0 +1,256 @@
+package checks
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	TLS Implementation = "tls"
+
+	// defaultTLSTimeout bounds connecting and handshaking when no timeout is configured.
+	defaultTLSTimeout = 10 * time.Second
+)
+
+var ErrCertificateExpiring = errors.New("certificate expires within the configured window")
+
+type tlsCheck struct {
+	*BaseCheck
+	host       string
+	port       int
+	file       string
+	within     time.Duration
+	warnWithin time.Duration
+	timeout    time.Duration
+	tlsConfig  *tls.Config
+}
+
+// TLSOption configures a TLS certificate check.
+type TLSOption func(*tlsCheck)
+
+// WithTLSWarnWithin reports certificates which expire within d, but not within the
+// check's failure window, as warnings in the result's details rather than failing.
+func WithTLSWarnWithin(d time.Duration) TLSOption {
+	return func(c *tlsCheck) {
+		c.warnWithin = d
+	}
+}
+
+// WithTLSTimeout bounds connecting and handshaking, defaults to 10 seconds.
+func WithTLSTimeout(timeout time.Duration) TLSOption {
+	return func(c *tlsCheck) {
+		c.timeout = timeout
+	}
+}
+
+// WithTLSCheckConfig sets the TLS configuration used to connect, e.g. to set the
+// ServerName or trust a private CA. If InsecureSkipVerify is set the chain is not verified.
+func WithTLSCheckConfig(cfg *tls.Config) TLSOption {
+	return func(c *tlsCheck) {
+		c.tlsConfig = cfg
+	}
+}
+
+// NewTLSCheck returns a check which connects over TLS to host:port and fails if any
+// certificate in the peer's chain expires within the duration within, or the chain is invalid.
+func NewTLSCheck(host string, port int, within time.Duration, opts ...TLSOption) (CheckInterface, error) {
+	check := tlsCheck{
+		BaseCheck: NewBaseCheck(),
+		host:      host,
+		port:      port,
+		within:    within,
+		timeout:   defaultTLSTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	return &check, nil
+}
+
+// NewTLSFileCheck returns a check which loads the PEM encoded certificates in file and fails
+// if any expires within the duration within. The file is re-read on every attempt.
+func NewTLSFileCheck(file string, within time.Duration, opts ...TLSOption) (CheckInterface, error) {
+	check := tlsCheck{
+		BaseCheck: NewBaseCheck(),
+		file:      file,
+		within:    within,
+		timeout:   defaultTLSTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&check)
+	}
+
+	return &check, nil
+}
+
+func (c *tlsCheck) GetImp() Implementation {
+	return TLS
+}
+
+func (c *tlsCheck) HealthCheck() error {
+	return c.Check(context.Background()).Err
+}
+
+func (c *tlsCheck) HealthCheckContext(ctx context.Context) error {
+	return c.Check(ctx).Err
+}
+
+func (c *tlsCheck) Check(ctx context.Context) Result {
+	start := time.Now()
+	c.SetStatus(STARTUP)
+
+	var certs []*x509.Certificate
+	var verify func() error
+	var err error
+	if c.file != "" {
+		c.SetStatus(CHECKING)
+		certs, err = c.loadCertificates()
+	} else {
+		ctx, cancel := context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+
+		c.SetStatus(CHECKING)
+		certs, verify, err = c.peerCertificates(ctx)
+	}
+	if err != nil {
+		return c.DoneResult(start, err, nil)
+	}
+
+	now := time.Now()
+	var expiring, warnings []string
+	certDetails := make([]map[string]any, 0, len(certs))
+	for _, cert := range certs {
+		certDetails = append(certDetails, map[string]any{
+			"subject":  cert.Subject.String(),
+			"issuer":   cert.Issuer.String(),
+			"notAfter": cert.NotAfter,
+		})
+
+		remaining := cert.NotAfter.Sub(now)
+		switch {
+		case remaining <= c.within:
+			expiring = append(expiring, describeCertificate(cert))
+		case remaining <= c.warnWithin:
+			warnings = append(warnings, describeCertificate(cert))
+		}
+	}
+
+	details := map[string]any{
+		"certificates": certDetails,
+	}
+	if len(warnings) > 0 {
+		details["warnings"] = warnings
+	}
+
+	if len(expiring) > 0 {
+		return c.DoneResult(start, errors.Wrap(ErrCertificateExpiring, strings.Join(expiring, "; ")), details)
+	}
+
+	if verify != nil {
+		if err := verify(); err != nil {
+			return c.DoneResult(start, errors.Wrap(err, "error verifying certificate chain"), details)
+		}
+	}
+
+	return c.DoneResult(start, nil, details)
+}
+
+// peerCertificates connects to the check's host and returns the peer's certificate chain,
+// along with a function verifying the chain unless verification is disabled.
+// Verification is deferred so certificates which have already expired can be reported.
+func (c *tlsCheck) peerCertificates(ctx context.Context) ([]*x509.Certificate, func() error, error) {
+	cfg := &tls.Config{}
+	if c.tlsConfig != nil {
+		cfg = c.tlsConfig.Clone()
+	}
+	if cfg.ServerName == "" {
+		cfg.ServerName = c.host
+	}
+	skipVerify := cfg.InsecureSkipVerify
+	cfg.InsecureSkipVerify = true
+
+	dialer := tls.Dialer{Config: cfg}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(c.host, strconv.Itoa(c.port)))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "error establishing tls connection")
+	}
+	defer conn.Close()
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return nil, nil, errors.New("tls peer presented no certificates")
+	}
+
+	if skipVerify {
+		return certs, nil, nil
+	}
+
+	verify := func() error {
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+
+		_, err := certs[0].Verify(x509.VerifyOptions{
+			DNSName:       cfg.ServerName,
+			Roots:         cfg.RootCAs,
+			Intermediates: intermediates,
+		})
+		return err
+	}
+
+	return certs, verify, nil
+}
+
+// loadCertificates returns the PEM encoded certificates in the check's file.
+func (c *tlsCheck) loadCertificates() ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(c.file)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading certificate file")
+	}
+
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrap(err, "error parsing certificate")
+		}
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.Errorf("no certificates found in %s", c.file)
+	}
+
+	return certs, nil
+}
+
+func (c *tlsCheck) Cleanup() {}
+
+// describeCertificate returns the subject, issuer and expiry of cert.
+func describeCertificate(cert *x509.Certificate) string {
+	return fmt.Sprintf("subject=%q issuer=%q notAfter=%s", cert.Subject.String(), cert.Issuer.String(), cert.NotAfter.UTC().Format(time.RFC3339))
+}
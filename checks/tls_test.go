@@ -0,0 +1,231 @@
+package checks
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// testTLSServer returns a new TLS server and the host, port and TLS configuration to reach it.
+func testTLSServer(t *testing.T) (string, int, *tls.Config) {
+	t.Helper()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+
+	host, portStr, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return host, port, server.Client().Transport.(*http.Transport).TLSClientConfig
+}
+
+// testCertificateFile writes a self-signed certificate expiring after validFor to a temporary file.
+func testCertificateFile(t *testing.T, validFor time.Duration) string {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "wibble.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(t.TempDir(), "cert.pem")
+	err = os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return file
+}
+
+func TestNewTLSCheck(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewTLSCheck("localhost", 1234, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	if aCheck.GetImp() != TLS {
+		t.Fatalf("tlsCheck.GetImp() returned unexpected value: %s", aCheck.GetImp())
+	}
+
+	if aCheck.GetStatus() != STARTUP {
+		t.Fatalf("tlsCheck.GetStatus() returned unexpected value after initialisation: %s", aCheck.GetStatus())
+	}
+
+	if aCheck.GetLastCheck().Equal(time.Time{}) {
+		t.Fatalf("tlsCheck.GetLastCheck() returned unexpected value after initialisation: %v", aCheck.GetLastCheck())
+	}
+
+	if aCheck.GetError() != nil {
+		t.Fatalf("tlsCheck.GetError() returned unexpected value after initialisation: %v", aCheck.GetError())
+	}
+}
+
+func TestTLSHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	host, port, cfg := testTLSServer(t)
+
+	aCheck, err := NewTLSCheck(host, port, 24*time.Hour, WithTLSCheckConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aCheck.GetStatus() != DONE {
+		t.Fatalf("tlsCheck.GetStatus() returned unexpected value after calling HealthCheck(): %s", aCheck.GetStatus())
+	}
+
+	if !(aCheck.GetLastCheck().Before(time.Now()) && aCheck.GetLastCheck().After(time.Time{})) {
+		t.Fatalf("tlsCheck.GetLastCheck() returned unexpected value after calling HealthCheck(): %v", aCheck.GetLastCheck())
+	}
+
+	res := ToV2(aCheck).Check(context.Background())
+	if res.Err != nil || len(res.Details["certificates"].([]map[string]any)) == 0 {
+		t.Fatalf("tlsCheck.Check() returned unexpected result: %+v", res)
+	}
+}
+
+func TestTLSHealthCheckExpiring(t *testing.T) {
+	t.Parallel()
+
+	host, port, cfg := testTLSServer(t)
+
+	// The test server's certificate is valid for decades, but not for centuries.
+	aCheck, err := NewTLSCheck(host, port, 200*365*24*time.Hour, WithTLSCheckConfig(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if !errors.Is(err, ErrCertificateExpiring) {
+		t.Fatalf("tlsCheck.HealthCheck() did not return expected error\nexpected: %v\ngot: %v", ErrCertificateExpiring, err)
+	}
+
+	if !strings.Contains(err.Error(), "subject=") || !strings.Contains(err.Error(), "notAfter=") {
+		t.Fatalf("tlsCheck.HealthCheck() error does not describe the certificate: %v", err)
+	}
+}
+
+func TestTLSHealthCheckUntrusted(t *testing.T) {
+	t.Parallel()
+
+	host, port, _ := testTLSServer(t)
+
+	aCheck, err := NewTLSCheck(host, port, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("tlsCheck.HealthCheck() did not return an error for an untrusted certificate")
+	}
+
+	aCheck, err = NewTLSCheck(host, port, 24*time.Hour, WithTLSCheckConfig(&tls.Config{InsecureSkipVerify: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err != nil {
+		t.Fatalf("tlsCheck.HealthCheck() returned an error with verification disabled: %v", err)
+	}
+}
+
+func TestTLSFileHealthCheck(t *testing.T) {
+	t.Parallel()
+
+	file := testCertificateFile(t, 10*24*time.Hour)
+
+	aCheck, err := NewTLSFileCheck(file, 24*time.Hour, WithTLSWarnWithin(30*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	res := ToV2(aCheck).Check(context.Background())
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	if warnings, ok := res.Details["warnings"].([]string); !ok || len(warnings) != 1 || !strings.Contains(warnings[0], "wibble.example.com") {
+		t.Fatalf("tlsCheck.Check() did not warn of a certificate expiring within the warning window: %v", res.Details)
+	}
+
+	aCheck, err = NewTLSFileCheck(file, 30*24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if !errors.Is(err, ErrCertificateExpiring) {
+		t.Fatalf("tlsCheck.HealthCheck() did not return expected error\nexpected: %v\ngot: %v", ErrCertificateExpiring, err)
+	}
+}
+
+func TestTLSFileHealthCheckNoFile(t *testing.T) {
+	t.Parallel()
+
+	aCheck, err := NewTLSFileCheck(filepath.Join(t.TempDir(), "wibble.pem"), 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aCheck.Cleanup()
+
+	err = aCheck.HealthCheck()
+	if err == nil {
+		t.Fatalf("tlsCheck.HealthCheck() did not return an error for a missing file")
+	}
+
+	if !errors.Is(aCheck.GetError(), err) {
+		t.Fatalf("tlsCheck.GetError() returned unexpected value after calling HealthCheck(): %v", aCheck.GetError())
+	}
+}
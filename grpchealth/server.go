@@ -0,0 +1,122 @@
+// Package grpchealth implements the gRPC Health Checking Protocol, grpc.health.v1.Health,
+// backed by a health manager.
+package grpchealth
+
+import (
+	"context"
+	"sync"
+
+	"github.com/LS6-Events/healthcheck"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var _ healthpb.HealthServer = (*Server)(nil)
+
+// Server is a grpc.health.v1.Health server reporting the state of a health manager.
+//
+// The empty service name reports the health manager as a whole, SERVING unless a critical
+// check is failing. Every registered check is also reported as a service using its name,
+// SERVING when the check's last attempt passed.
+type Server struct {
+	healthpb.UnimplementedHealthServer
+	hm *healthcheck.HealthManager
+
+	mtx      sync.Mutex
+	watchers map[chan struct{}]struct{}
+}
+
+// NewServer returns a new Server for hm.
+// Watch streams changes as the health manager runs checks, e.g. whilst monitoring with Start.
+func NewServer(hm *healthcheck.HealthManager) *Server {
+	s := &Server{
+		hm:       hm,
+		watchers: make(map[chan struct{}]struct{}),
+	}
+
+	hm.AddHooks(healthcheck.Hooks{
+		RoundFinished: s.notify,
+	})
+
+	return s
+}
+
+// Check implements grpc.health.v1.Health.Check.
+// Returns a NotFound error if service is neither empty nor the name of a registered check.
+func (s *Server) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	st, ok := s.status(req.GetService())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+
+	return &healthpb.HealthCheckResponse{Status: st}, nil
+}
+
+// Watch implements grpc.health.v1.Health.Watch.
+// Sends the service's current status, then a new status whenever it changes.
+// Unknown services are reported as SERVICE_UNKNOWN, which changes if a check is later registered with the name.
+func (s *Server) Watch(req *healthpb.HealthCheckRequest, stream healthpb.Health_WatchServer) error {
+	update := make(chan struct{}, 1)
+
+	s.mtx.Lock()
+	s.watchers[update] = struct{}{}
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		delete(s.watchers, update)
+		s.mtx.Unlock()
+	}()
+
+	var last healthpb.HealthCheckResponse_ServingStatus = -1
+	for {
+		st, _ := s.status(req.GetService())
+		if st != last {
+			err := stream.Send(&healthpb.HealthCheckResponse{Status: st})
+			if err != nil {
+				return status.Error(codes.Canceled, "stream has ended")
+			}
+			last = st
+		}
+
+		select {
+		case <-stream.Context().Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-update:
+		}
+	}
+}
+
+// status returns the serving status of service, false if the service is unknown.
+func (s *Server) status(service string) (healthpb.HealthCheckResponse_ServingStatus, bool) {
+	if service == "" {
+		if s.hm.GetHealth() {
+			return healthpb.HealthCheckResponse_SERVING, true
+		}
+		return healthpb.HealthCheckResponse_NOT_SERVING, true
+	}
+
+	report, ok := s.hm.GetReport(service)
+	if !ok {
+		return healthpb.HealthCheckResponse_SERVICE_UNKNOWN, false
+	}
+
+	if report.Passed() {
+		return healthpb.HealthCheckResponse_SERVING, true
+	}
+	return healthpb.HealthCheckResponse_NOT_SERVING, true
+}
+
+// notify wakes every watcher to re-evaluate its service's status.
+func (s *Server) notify(ctx context.Context, state healthcheck.HealthState) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for update := range s.watchers {
+		select {
+		case update <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package grpchealth
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/LS6-Events/healthcheck"
+	"github.com/LS6-Events/healthcheck/checks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const TEST checks.Implementation = "test"
+
+var errTestCheck = errors.New("test check is configured to fail")
+
+type testCheck struct {
+	fail atomic.Bool
+}
+
+func (c *testCheck) GetImp() checks.Implementation {
+	return TEST
+}
+
+func (c *testCheck) Check(ctx context.Context) checks.Result {
+	res := checks.Result{
+		Status:    checks.DONE,
+		Timestamp: time.Now(),
+	}
+
+	if c.fail.Load() {
+		res.Err = errTestCheck
+	}
+
+	return res
+}
+
+func (c *testCheck) Cleanup() {}
+
+// testClient serves a new Server for hm over an in-memory connection and returns a client for it.
+func testClient(t *testing.T, hm *healthcheck.HealthManager) healthpb.HealthClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	healthpb.RegisterHealthServer(srv, NewServer(hm))
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestServerCheck(t *testing.T) {
+	aHealthManager, err := healthcheck.New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := &testCheck{}
+	aCheck.fail.Store(true)
+	err = aHealthManager.RegisterV2(aCheck, healthcheck.WithName("geolocation"), healthcheck.WithCriticality(healthcheck.OPTIONAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClient(t, aHealthManager)
+	ctx := context.Background()
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Check() returned unexpected status before checks ran: %s", resp.GetStatus())
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("Check() returned unexpected status with only an optional check failing: %s", resp.GetStatus())
+	}
+
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "geolocation"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_NOT_SERVING {
+		t.Fatalf("Check() returned unexpected status for a failing check: %s", resp.GetStatus())
+	}
+
+	_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "wibble"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("Check() did not return expected error for an unknown service\nexpected: %v\ngot: %v", codes.NotFound, err)
+	}
+}
+
+func TestServerWatch(t *testing.T) {
+	aHealthManager, err := healthcheck.New(10*time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := &testCheck{}
+	err = aHealthManager.RegisterV2(aCheck, healthcheck.WithName("postgres"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := testClient(t, aHealthManager)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "postgres"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectStatus := func(expected healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+
+		resp, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.GetStatus() != expected {
+			t.Fatalf("Watch() streamed unexpected status\nexpected: %s\ngot: %s", expected, resp.GetStatus())
+		}
+	}
+
+	expectStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	err = aHealthManager.Start(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Stop()
+
+	expectStatus(healthpb.HealthCheckResponse_SERVING)
+
+	aCheck.fail.Store(true)
+	expectStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+
+	aCheck.fail.Store(false)
+	expectStatus(healthpb.HealthCheckResponse_SERVING)
+}
@@ -0,0 +1,126 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/LS6-Events/healthcheck/checks"
+)
+
+const (
+	// StatusOK is reported by the HTTP handler when the health manager is healthy.
+	StatusOK = "ok"
+	// StatusDegraded is reported by the HTTP handler when only optional checks are failing.
+	StatusDegraded = "degraded"
+	// StatusUnavailable is reported by the HTTP handler when the health manager is unhealthy.
+	StatusUnavailable = "unavailable"
+)
+
+// healthResponse is the JSON body written by the HTTP handler.
+type healthResponse struct {
+	Status string          `json:"status"`
+	Checks []checkResponse `json:"checks,omitempty"`
+}
+
+// checkResponse describes a single check in a healthResponse.
+type checkResponse struct {
+	Name           string                `json:"name"`
+	Description    string                `json:"description,omitempty"`
+	Tags           []string              `json:"tags,omitempty"`
+	Criticality    Criticality           `json:"criticality"`
+	Implementation checks.Implementation `json:"implementation"`
+	Status         checks.CheckStatus    `json:"status"`
+	LastCheck      time.Time             `json:"lastCheck"`
+	Latency        string                `json:"latency"`
+	Error          string                `json:"error,omitempty"`
+	Details        map[string]any        `json:"details,omitempty"`
+	Excluded       bool                  `json:"excluded,omitempty"`
+}
+
+// NewHandler returns a http.Handler exposing the health manager's state on three endpoints.
+//
+// /livez reports whether the process is alive, it always responds 200 as failing
+// dependencies are not fixed by restarting the process.
+// /readyz responds 200 when every critical check's last attempt passed, otherwise 503.
+// If only optional checks are failing the body's status is "degraded".
+// /healthz responds as /readyz, always listing every registered check.
+//
+// All endpoints respond with a JSON body, which lists every registered check when the
+// `verbose` query parameter is present. Checks can be ignored using one or more
+// `exclude=<name>` query parameters.
+func NewHandler(hm *HealthManager) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/livez", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, r, hm, false, false)
+	})
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, r, hm, true, false)
+	})
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeHealth(w, r, hm, true, true)
+	})
+
+	return mux
+}
+
+// writeHealth writes the health manager's state to w.
+// If checked is false the response is always 200, otherwise 503 if any critical check not excluded is failing.
+func writeHealth(w http.ResponseWriter, r *http.Request, hm *HealthManager, checked, verbose bool) {
+	query := r.URL.Query()
+	_, isVerbose := query["verbose"]
+	verbose = verbose || isVerbose
+
+	excluded := make(map[string]bool)
+	for _, e := range query["exclude"] {
+		excluded[e] = true
+	}
+
+	resp := healthResponse{
+		Status: StatusOK,
+	}
+
+	included := make([]CheckReport, 0)
+	for _, report := range hm.GetReports() {
+		isExcluded := excluded[report.Name]
+		if !isExcluded {
+			included = append(included, report)
+		}
+
+		if verbose {
+			cr := checkResponse{
+				Name:           report.Name,
+				Description:    report.Description,
+				Tags:           report.Tags,
+				Criticality:    report.Criticality,
+				Implementation: report.Imp,
+				Status:         report.Result.Status,
+				LastCheck:      report.Result.Timestamp,
+				Latency:        report.Result.Latency.String(),
+				Details:        report.Result.Details,
+				Excluded:       isExcluded,
+			}
+			if report.Result.Err != nil {
+				cr.Error = report.Result.Err.Error()
+			}
+			resp.Checks = append(resp.Checks, cr)
+		}
+	}
+
+	code := http.StatusOK
+	if checked {
+		switch stateOf(included) {
+		case DEGRADED:
+			resp.Status = StatusDegraded
+		case UNHEALTHY:
+			resp.Status = StatusUnavailable
+			code = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(resp)
+}
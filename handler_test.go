@@ -0,0 +1,147 @@
+package healthcheck
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// getHealth requests target from a new handler for hm and decodes the response.
+func getHealth(t *testing.T, hm *HealthManager, target string) (int, healthResponse) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	NewHandler(hm).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+
+	var resp healthResponse
+	err := json.NewDecoder(rec.Body).Decode(&resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rec.Code, resp
+}
+
+func TestHandler(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, _ := getHealth(t, aHealthManager, "/readyz")
+	if code != http.StatusServiceUnavailable {
+		t.Fatalf("/readyz returned unexpected status code before checks ran: %d", code)
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, resp := getHealth(t, aHealthManager, "/readyz")
+	if code != http.StatusOK || resp.Status != StatusOK {
+		t.Fatalf("/readyz returned unexpected response after checks passed: %d %s", code, resp.Status)
+	}
+
+	if len(resp.Checks) != 0 {
+		t.Fatalf("/readyz listed checks without the verbose query parameter: %v", resp.Checks)
+	}
+
+	code, resp = getHealth(t, aHealthManager, "/healthz")
+	if code != http.StatusOK || resp.Status != StatusOK {
+		t.Fatalf("/healthz returned unexpected response after checks passed: %d %s", code, resp.Status)
+	}
+
+	if len(resp.Checks) != 1 || resp.Checks[0].Implementation != TEST || resp.Checks[0].Error != "" {
+		t.Fatalf("/healthz returned unexpected checks: %v", resp.Checks)
+	}
+}
+
+func TestHandlerFailing(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCheck := NewSwitchCheck()
+	aCheck.fail.Store(true)
+	err = aHealthManager.Register(aCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if err == nil {
+		t.Fatal("Run() did not return an error for a failing check")
+	}
+
+	code, resp := getHealth(t, aHealthManager, "/readyz?verbose")
+	if code != http.StatusServiceUnavailable || resp.Status != StatusUnavailable {
+		t.Fatalf("/readyz returned unexpected response with a failing check: %d %s", code, resp.Status)
+	}
+
+	if len(resp.Checks) != 2 || resp.Checks[1].Error != ErrFailCheck.Error() {
+		t.Fatalf("/readyz?verbose returned unexpected checks: %v", resp.Checks)
+	}
+
+	code, resp = getHealth(t, aHealthManager, "/readyz?exclude=switch")
+	if code != http.StatusOK || resp.Status != StatusOK {
+		t.Fatalf("/readyz?exclude=switch returned unexpected response: %d %s", code, resp.Status)
+	}
+
+	code, resp = getHealth(t, aHealthManager, "/livez")
+	if code != http.StatusOK || resp.Status != StatusOK {
+		t.Fatalf("/livez returned unexpected response with a failing check: %d %s", code, resp.Status)
+	}
+}
+
+func TestHandlerDegraded(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Register(NewFailCheck(), WithName("geolocation"), WithCriticality(OPTIONAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, resp := getHealth(t, aHealthManager, "/readyz")
+	if code != http.StatusOK || resp.Status != StatusDegraded {
+		t.Fatalf("/readyz returned unexpected response with an optional check failing: %d %s", code, resp.Status)
+	}
+
+	code, resp = getHealth(t, aHealthManager, "/healthz?exclude=geolocation")
+	if code != http.StatusOK || resp.Status != StatusOK {
+		t.Fatalf("/healthz?exclude=geolocation returned unexpected response: %d %s", code, resp.Status)
+	}
+
+	if len(resp.Checks) != 2 || resp.Checks[1].Criticality != OPTIONAL || !resp.Checks[1].Excluded {
+		t.Fatalf("/healthz returned unexpected checks: %v", resp.Checks)
+	}
+}
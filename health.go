@@ -1,6 +1,7 @@
 package healthcheck
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -12,14 +13,81 @@ import (
 var ErrInvalidConfig = errors.New("invalid health manager configuration")
 var ErrInvalidCheck = errors.New("cannot register invalid check")
 var ErrTimeout = errors.New("health check timed out with checks failing")
+var ErrAlreadyStarted = errors.New("health manager is already monitoring checks")
+var ErrDuplicateCheck = errors.New("cannot register check with a duplicate name")
 
 type HealthManager struct {
 	checkFreq time.Duration
 	timeout   time.Duration
-	healthy   bool
-	mtx       sync.Mutex
-	checks    []checks.CheckInterface
+	state     HealthState
+	// mtx serialises registering, running and cleaning up checks.
+	mtx sync.Mutex
+	// stateMtx guards the state which is read or changed whilst checks run,
+	// state, checks, each check's result, hooks and the monitoring fields.
+	stateMtx  sync.RWMutex
+	checks    []*registeredCheck
 	startTime time.Time
+	stop      context.CancelFunc
+	stopped   chan struct{}
+	hooks     []Hooks
+}
+
+// registeredCheck is a check registered with a health manager and the result of its last attempt.
+type registeredCheck struct {
+	check       checks.CheckInterfaceV2
+	name        string
+	description string
+	tags        []string
+	criticality Criticality
+	result      checks.Result
+	// attempted is set once the check has been attempted, whatever its result.
+	attempted bool
+}
+
+// RegisterOption configures a check when it is registered with a health manager.
+type RegisterOption func(*registeredCheck)
+
+// WithName sets the name which identifies the check within the health manager.
+// Names must be unique, if not set the check's Implementation is used, suffixed
+// with a number if another check already uses it, e.g. "postgres-2".
+func WithName(name string) RegisterOption {
+	return func(rc *registeredCheck) {
+		rc.name = name
+	}
+}
+
+// WithDescription sets a human readable description of the check.
+func WithDescription(description string) RegisterOption {
+	return func(rc *registeredCheck) {
+		rc.description = description
+	}
+}
+
+// WithTags sets arbitrary tags on the check, e.g. "database" or "primary".
+func WithTags(tags ...string) RegisterOption {
+	return func(rc *registeredCheck) {
+		rc.tags = append(rc.tags, tags...)
+	}
+}
+
+// CheckReport describes a registered check and the result of its last attempt.
+type CheckReport struct {
+	Name        string
+	Description string
+	Tags        []string
+	Criticality Criticality
+	Imp         checks.Implementation
+	Result      checks.Result
+	// Abandoned is set in reports passed to Hooks.CheckFinished when the attempt's round was
+	// abandoned, because monitoring stopped or the context of RunContext ended, so its result
+	// was not recorded.
+	Abandoned bool
+	attempted bool
+}
+
+// Passed returns whether the check has been attempted and its last attempt succeeded.
+func (r CheckReport) Passed() bool {
+	return r.attempted && r.Result.Err == nil
 }
 
 // New returns a new HealthManager instance.
@@ -35,85 +103,392 @@ func New(CheckFrequency, timeout time.Duration) (*HealthManager, error) {
 	return &HealthManager{
 		checkFreq: CheckFrequency,
 		timeout:   timeout,
-		healthy:   false,
-		checks:    make([]checks.CheckInterface, 0),
+		state:     UNHEALTHY,
+		checks:    make([]*registeredCheck, 0),
 	}, nil
 }
 
 // GetHealth returns the health manager's current healthy status.
+// The health manager is healthy unless a critical check is failing, see GetState.
 func (hm *HealthManager) GetHealth() bool {
-	return hm.healthy
+	return hm.GetState() != UNHEALTHY
+}
+
+// GetState returns the health manager's current state.
+func (hm *HealthManager) GetState() HealthState {
+	hm.stateMtx.RLock()
+	defer hm.stateMtx.RUnlock()
+
+	return hm.state
+}
+
+// GetReports returns a report for every registered check, in the order they were registered.
+func (hm *HealthManager) GetReports() []CheckReport {
+	hm.stateMtx.RLock()
+	defer hm.stateMtx.RUnlock()
+
+	return hm.reports()
+}
+
+// GetReport returns the report for the check registered with name.
+// Returns false if no check is registered with name.
+func (hm *HealthManager) GetReport(name string) (CheckReport, bool) {
+	hm.stateMtx.RLock()
+	defer hm.stateMtx.RUnlock()
+
+	rc := hm.find(name)
+	if rc == nil {
+		return CheckReport{}, false
+	}
+	return rc.report(), true
 }
 
 // Register registers a new check with the health manager.
-func (hm *HealthManager) Register(c checks.CheckInterface) error {
+// Returns ErrDuplicateCheck if the check's name is already in use.
+func (hm *HealthManager) Register(c checks.CheckInterface, opts ...RegisterOption) error {
+	return hm.RegisterV2(checks.ToV2(c), opts...)
+}
+
+// RegisterV2 registers a new context aware check with the health manager.
+// Returns ErrDuplicateCheck if the check's name is already in use.
+func (hm *HealthManager) RegisterV2(c checks.CheckInterfaceV2, opts ...RegisterOption) error {
 	if c == nil {
 		return ErrInvalidCheck
 	}
 
+	rc := &registeredCheck{
+		check:       c,
+		criticality: CRITICAL,
+		result: checks.Result{
+			Status:    checks.STARTUP,
+			Timestamp: time.Unix(0, 0),
+		},
+	}
+	for _, opt := range opts {
+		opt(rc)
+	}
+
 	hm.mtx.Lock()
 	defer hm.mtx.Unlock()
 
-	hm.checks = append(hm.checks, c)
+	hm.stateMtx.Lock()
+	defer hm.stateMtx.Unlock()
+
+	if rc.name != "" {
+		if hm.find(rc.name) != nil {
+			return ErrDuplicateCheck
+		}
+	} else {
+		rc.name = string(c.GetImp())
+		for i := 2; hm.find(rc.name) != nil; i++ {
+			rc.name = fmt.Sprintf("%s-%d", c.GetImp(), i)
+		}
+	}
+
+	hm.checks = append(hm.checks, rc)
 	return nil
 }
 
+// reports returns a report for every registered check.
+// Must be called with stateMtx held.
+func (hm *HealthManager) reports() []CheckReport {
+	reports := make([]CheckReport, 0, len(hm.checks))
+	for _, rc := range hm.checks {
+		reports = append(reports, rc.report())
+	}
+	return reports
+}
+
+// find returns the check registered with name, or nil if there is none.
+// Must be called with stateMtx held.
+func (hm *HealthManager) find(name string) *registeredCheck {
+	for _, rc := range hm.checks {
+		if rc.name == name {
+			return rc
+		}
+	}
+	return nil
+}
+
+// report returns a report describing the check.
+// Must be called with stateMtx held.
+func (rc *registeredCheck) report() CheckReport {
+	return CheckReport{
+		Name:        rc.name,
+		Description: rc.description,
+		Tags:        rc.tags,
+		Criticality: rc.criticality,
+		Imp:         rc.check.GetImp(),
+		Result:      rc.result,
+		attempted:   rc.attempted,
+	}
+}
+
 // Run executes the registered checks until all return healthy or the timeout elapses.
+// Failing OPTIONAL checks do not prevent Run from succeeding, the state is then DEGRADED.
 // Runs all checks then sleeps until the check frequency elapses before re-running checks.
 // New checks cannot be registerd whilst Run is ongoing.
 func (hm *HealthManager) Run() error {
+	return hm.RunContext(context.Background())
+}
+
+// RunContext behaves as Run, but also stops as soon as ctx is cancelled or its deadline passes.
+// The context is passed down to every check which implements checks.ContextCheckInterface or checks.CheckInterfaceV2.
+// If ctx ends first, ctx.Err() is returned wrapped with the last failure of each failing check.
+// A round cut short by ctx or the timeout is discarded, as its failures are those of the context.
+func (hm *HealthManager) RunContext(ctx context.Context) error {
 	hm.mtx.Lock()
 	defer hm.mtx.Unlock()
 
-	hm.healthy = false
+	// Whilst monitoring the state reflects the latest results, so it is left as it is.
+	hm.stateMtx.Lock()
+	if !hm.monitoring() {
+		hm.state = UNHEALTHY
+	}
+	hm.stateMtx.Unlock()
 	hm.startTime = time.Now()
 
-	for !hm.healthy {
-		var wg sync.WaitGroup
-		for i := 0; i < len(hm.checks); i++ {
-			wg.Add(1)
-			x := i
-			go func() {
-				hm.checks[x].HealthCheck()
-				wg.Done()
-			}()
-		}
-		wg.Wait()
+	runCtx, cancel := context.WithDeadline(ctx, hm.startTime.Add(hm.timeout))
+	defer cancel()
 
-		var failed bool = false
-		for i := 0; i < len(hm.checks); i++ {
-			if hm.checks[i].GetError() != nil {
-				failed = true
-			}
+	for {
+		state, abandoned := hm.runRound(runCtx, runCtx.Done())
+		if !abandoned && state != UNHEALTHY {
+			return nil
 		}
 
-		if !failed {
-			hm.healthy = true
-			return nil
+		if ctx.Err() != nil {
+			return hm.wrapFailures(ctx.Err())
 		}
 
 		if hm.startTime.Add(hm.timeout).Before(time.Now()) {
-			var timeoutErr error = ErrTimeout
-			for i := 0; i < len(hm.checks); i++ {
-				if hm.checks[i].GetError() != nil {
-					timeoutErr = errors.Wrap(timeoutErr, fmt.Sprintf("%s:%s", hm.checks[i].GetImp(), hm.checks[i].GetError().Error()))
-				}
-			}
-			return timeoutErr
+			return hm.wrapFailures(ErrTimeout)
 		}
 
-		time.Sleep(hm.checkFreq)
+		wait := time.NewTimer(hm.checkFreq)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return hm.wrapFailures(ctx.Err())
+		case <-wait.C:
+		}
 	}
+}
+
+// Start begins monitoring the registered checks in the background.
+// All checks are re-run every check frequency until Stop is called or ctx is cancelled,
+// with each round of checks bounded by the health manager's timeout.
+// Whilst monitoring, GetHealth and each check's state reflect the latest results.
+func (hm *HealthManager) Start(ctx context.Context) error {
+	hm.stateMtx.Lock()
+	defer hm.stateMtx.Unlock()
 
+	if hm.monitoring() {
+		return ErrAlreadyStarted
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	hm.stop = cancel
+	hm.stopped = make(chan struct{})
+
+	go hm.monitor(ctx, hm.stopped)
 	return nil
 }
 
+// Stop stops monitoring started by Start, waiting for any ongoing round of checks to be abandoned.
+// Calling Stop when the health manager is not monitoring is a no-op.
+func (hm *HealthManager) Stop() {
+	hm.stateMtx.Lock()
+	stop, stopped := hm.stop, hm.stopped
+	hm.stop, hm.stopped = nil, nil
+	hm.stateMtx.Unlock()
+
+	if stop == nil {
+		return
+	}
+
+	stop()
+	<-stopped
+}
+
+// monitoring returns whether monitoring started by Start is ongoing.
+// Must be called with stateMtx held.
+func (hm *HealthManager) monitoring() bool {
+	if hm.stopped == nil {
+		return false
+	}
+
+	select {
+	case <-hm.stopped:
+		return false
+	default:
+		return true
+	}
+}
+
+// monitor re-runs the registered checks every check frequency until ctx is done.
+func (hm *HealthManager) monitor(ctx context.Context, stopped chan struct{}) {
+	defer close(stopped)
+
+	for {
+		hm.mtx.Lock()
+		checkCtx, cancel := context.WithTimeout(ctx, hm.timeout)
+		hm.runRound(checkCtx, ctx.Done())
+		cancel()
+		hm.mtx.Unlock()
+
+		wait := time.NewTimer(hm.checkFreq)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			return
+		case <-wait.C:
+		}
+	}
+}
+
+// runRound runs every registered check and, unless abandon is closed by the time they finish,
+// records their results and updates the health manager's state from them.
+// Returns the health manager's state after the round and whether the round was abandoned.
+// Must be called with mtx held.
+func (hm *HealthManager) runRound(ctx context.Context, abandon <-chan struct{}) (HealthState, bool) {
+	// Hooks added during the round are called from the next round.
+	hm.stateMtx.RLock()
+	hooks := append([]Hooks(nil), hm.hooks...)
+	hm.stateMtx.RUnlock()
+
+	// Each hook's finished callback is given the context returned by its own started callback.
+	hookCtxs := make([]context.Context, len(hooks))
+	for i, h := range hooks {
+		if h.RoundStarted != nil {
+			ctx = h.RoundStarted(ctx)
+		}
+		hookCtxs[i] = ctx
+	}
+
+	results := hm.runChecks(ctx, hooks)
+
+	// Checks abandoned because monitoring stopped, or the context of RunContext ended, say
+	// nothing about the dependencies, so neither their results nor the resulting state are kept.
+	abandoned := false
+	hm.stateMtx.Lock()
+	select {
+	case <-abandon:
+		abandoned = true
+		for i, rc := range hm.checks {
+			rc.result.Status = results[i].previous
+		}
+	default:
+		for i, rc := range hm.checks {
+			rc.result = results[i].result
+			rc.attempted = true
+		}
+		hm.state = stateOf(hm.reports())
+	}
+	state := hm.state
+	hm.stateMtx.Unlock()
+
+	// Check hooks are only called once the round is known to have been kept or abandoned.
+	for _, res := range results {
+		res.report.Abandoned = abandoned
+		for i, h := range hooks {
+			if h.CheckFinished != nil {
+				h.CheckFinished(res.hookCtxs[i], res.report)
+			}
+		}
+	}
+
+	for i, h := range hooks {
+		if h.RoundFinished != nil {
+			h.RoundFinished(hookCtxs[i], state)
+		}
+	}
+
+	return state, abandoned
+}
+
+// roundResult is the result of a check's attempt during a round, the status the check
+// had before the round, and the report and contexts for the check's finished hooks.
+type roundResult struct {
+	result   checks.Result
+	previous checks.CheckStatus
+	report   CheckReport
+	hookCtxs []context.Context
+}
+
+// runChecks runs every registered check concurrently and waits for them all to finish,
+// returning their results in the order the checks were registered.
+// Must be called with mtx held.
+func (hm *HealthManager) runChecks(ctx context.Context, hooks []Hooks) []roundResult {
+	results := make([]roundResult, len(hm.checks))
+
+	hm.stateMtx.Lock()
+	for i, rc := range hm.checks {
+		results[i].previous = rc.result.Status
+		rc.result.Status = checks.CHECKING
+	}
+	hm.stateMtx.Unlock()
+
+	var wg sync.WaitGroup
+	for i := 0; i < len(hm.checks); i++ {
+		wg.Add(1)
+		x := i
+		go func() {
+			hm.runCheck(ctx, hm.checks[x], hooks, &results[x])
+			wg.Done()
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// runCheck attempts a single check, calling any started hooks, and sets its result in res.
+// Must be called with mtx held.
+func (hm *HealthManager) runCheck(ctx context.Context, rc *registeredCheck, hooks []Hooks, res *roundResult) {
+	hm.stateMtx.RLock()
+	report := rc.report()
+	hm.stateMtx.RUnlock()
+
+	hookCtxs := make([]context.Context, len(hooks))
+	for i, h := range hooks {
+		if h.CheckStarted != nil {
+			ctx = h.CheckStarted(ctx, report)
+		}
+		hookCtxs[i] = ctx
+	}
+
+	res.result = rc.check.Check(ctx)
+	if res.result.Timestamp.IsZero() {
+		res.result.Timestamp = time.Now()
+	}
+
+	report.Result = res.result
+	report.attempted = true
+	res.report = report
+	res.hookCtxs = hookCtxs
+}
+
+// wrapFailures wraps err with the last error of every failing check.
+// Must be called with mtx held.
+func (hm *HealthManager) wrapFailures(err error) error {
+	for i := 0; i < len(hm.checks); i++ {
+		if hm.checks[i].result.Err != nil {
+			err = errors.Wrap(err, fmt.Sprintf("%s:%s", hm.checks[i].name, hm.checks[i].result.Err.Error()))
+		}
+	}
+	return err
+}
+
 // Cleanup cleans up any resources required by the health manager and any registered checks.
+// Stops monitoring if it was started.
 func (hm *HealthManager) Cleanup() {
+	hm.Stop()
+
 	hm.mtx.Lock()
 	defer hm.mtx.Unlock()
 
-	for _, check := range hm.checks {
-		check.Cleanup()
+	for _, rc := range hm.checks {
+		rc.check.Cleanup()
 	}
 }
@@ -1,7 +1,10 @@
 package healthcheck
 
 import (
+	"context"
 	"errors"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -11,8 +14,9 @@ import (
 // ========== Custom checks required for unit testing ==========
 
 const (
-	TEST checks.Implementation = "test"
-	FAIL checks.Implementation = "fail"
+	TEST   checks.Implementation = "test"
+	FAIL   checks.Implementation = "fail"
+	SWITCH checks.Implementation = "switch"
 )
 
 var ErrFailCheck = errors.New("fail check is configured to always fail")
@@ -29,6 +33,11 @@ type failCheck struct {
 	err       error
 }
 
+type switchCheck struct {
+	*checks.BaseCheck
+	fail atomic.Bool
+}
+
 func NewTestCheck() checks.CheckInterface {
 	check := testCheck{
 		status:    checks.STARTUP,
@@ -109,6 +118,44 @@ func (c *failCheck) HealthCheck() error {
 
 func (c *failCheck) Cleanup() {}
 
+func NewSwitchCheck() *switchCheck {
+	check := switchCheck{
+		BaseCheck: checks.NewBaseCheck(),
+	}
+
+	return &check
+}
+
+func (c *switchCheck) GetImp() checks.Implementation {
+	return SWITCH
+}
+
+func (c *switchCheck) HealthCheck() error {
+	c.SetStatus(checks.STARTUP)
+
+	c.SetStatus(checks.CHECKING)
+
+	if c.fail.Load() {
+		return c.Done(ErrFailCheck)
+	}
+
+	return c.Done(nil)
+}
+
+func (c *switchCheck) Cleanup() {}
+
+// waitForHealth polls hm until GetHealth returns expected or the wait times out.
+func waitForHealth(hm *HealthManager, expected bool) bool {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if hm.GetHealth() == expected {
+			return true
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return false
+}
+
 // ========== Unit Tests ==========
 
 func TestNew(t *testing.T) {
@@ -163,6 +210,82 @@ func TestRegister(t *testing.T) {
 	}
 }
 
+func TestRegisterV2(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.RegisterV2(checks.ToV2(NewTestCheck()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.RegisterV2(checks.ToV2(NewFailCheck()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Run() did not returned expected error for a failing test\nexpected: %v\ngot: %v", ErrTimeout, err)
+	}
+
+	err = aHealthManager.RegisterV2(nil)
+	if err != ErrInvalidCheck {
+		t.Fatalf("RegisterV2() did not returned expected error\nexpected: %v\ngot: %v", ErrInvalidCheck, err)
+	}
+}
+
+func TestRegisterNamed(t *testing.T) {
+	t.Parallel()
+
+	aHealthManager, err := New(time.Second, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck(), WithName("primary"), WithDescription("primary database"), WithTags("database", "primary"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Register(NewTestCheck(), WithName("primary"))
+	if err != ErrDuplicateCheck {
+		t.Fatalf("Register() did not returned expected error\nexpected: %v\ngot: %v", ErrDuplicateCheck, err)
+	}
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, ok := aHealthManager.GetReport("primary")
+	if !ok {
+		t.Fatal("GetReport() did not find check registered with WithName()")
+	}
+
+	if report.Description != "primary database" || len(report.Tags) != 2 || report.Imp != TEST {
+		t.Fatalf("GetReport() returned unexpected report: %+v", report)
+	}
+
+	reports := aHealthManager.GetReports()
+	if len(reports) != 3 || reports[1].Name != string(TEST) || reports[2].Name != string(TEST)+"-2" {
+		t.Fatalf("GetReports() returned unexpected default names: %+v", reports)
+	}
+
+	if _, ok := aHealthManager.GetReport("wibble"); ok {
+		t.Fatal("GetReport() found a check which was not registered")
+	}
+}
+
 func TestRegisterInvalid(t *testing.T) {
 	t.Parallel()
 
@@ -196,6 +319,41 @@ func TestHealthManagerRun(t *testing.T) {
 	}
 }
 
+// staleCheck passes without ever updating its last check time.
+type staleCheck struct {
+	testCheck
+}
+
+func (c *staleCheck) GetLastCheck() time.Time {
+	return time.Unix(0, 0)
+}
+
+func (c *staleCheck) HealthCheck() error {
+	return nil
+}
+
+func TestHealthManagerRunStaleLastCheck(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(&staleCheck{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !aHealthManager.GetHealth() {
+		t.Fatalf("GetHealth() returned unexpected value for a passing check which does not update its last check time")
+	}
+}
+
 func TestHealthManagerRunFail(t *testing.T) {
 	aHealthManager, err := New(time.Millisecond, time.Second)
 	if err != nil {
@@ -214,6 +372,248 @@ func TestHealthManagerRunFail(t *testing.T) {
 	}
 }
 
+func TestHealthManagerRunContext(t *testing.T) {
+	aHealthManager, err := New(time.Second, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.RunContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aHealthManager.GetHealth() != true {
+		t.Fatal("GetHealth() returned unexpected value after RunContext() succeeded")
+	}
+}
+
+func TestHealthManagerRunContextCancel(t *testing.T) {
+	aHealthManager, err := New(time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewFailCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err = aHealthManager.RunContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RunContext() did not returned expected error for a cancelled context\nexpected: %v\ngot: %v", context.DeadlineExceeded, err)
+	}
+
+	if !strings.Contains(err.Error(), ErrFailCheck.Error()) {
+		t.Fatalf("RunContext() error did not include the failing check's error: %v", err)
+	}
+
+	if time.Since(start) > 10*time.Second {
+		t.Fatalf("RunContext() did not return promptly after the context was cancelled")
+	}
+}
+
+func TestHealthManagerStart(t *testing.T) {
+	aHealthManager, err := New(10*time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := NewSwitchCheck()
+	err = aHealthManager.Register(aCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Stop()
+
+	if !waitForHealth(aHealthManager, true) {
+		t.Fatal("GetHealth() did not become healthy whilst monitoring a passing check")
+	}
+
+	aCheck.fail.Store(true)
+	if !waitForHealth(aHealthManager, false) {
+		t.Fatal("GetHealth() did not become unhealthy after the check started failing")
+	}
+
+	aCheck.fail.Store(false)
+	if !waitForHealth(aHealthManager, true) {
+		t.Fatal("GetHealth() did not recover after the check started passing")
+	}
+}
+
+func TestHealthManagerConcurrentReads(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := NewSwitchCheck()
+	err = aHealthManager.Register(aCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Stop()
+
+	// Run with -race, reads whilst checks run must not race with the monitoring goroutine.
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		aHealthManager.GetHealth()
+		aCheck.GetStatus()
+		aCheck.GetLastCheck()
+		aCheck.GetError()
+		aCheck.fail.Store(!aCheck.fail.Load())
+	}
+}
+
+func TestHealthManagerStartTwice(t *testing.T) {
+	aHealthManager, err := New(time.Second, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Start(context.Background())
+	if err != ErrAlreadyStarted {
+		t.Fatalf("Start() did not returned expected error\nexpected: %v\ngot: %v", ErrAlreadyStarted, err)
+	}
+
+	aHealthManager.Stop()
+
+	err = aHealthManager.Start(context.Background())
+	if err != nil {
+		t.Fatalf("Start() returned unexpected error after Stop(): %v", err)
+	}
+	aHealthManager.Stop()
+}
+
+func TestHealthManagerRunDegraded(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	if aHealthManager.GetState() != UNHEALTHY {
+		t.Fatalf("GetState() returned unexpected value after initialisation: %s", aHealthManager.GetState())
+	}
+
+	err = aHealthManager.Register(NewTestCheck())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Register(NewFailCheck(), WithCriticality(OPTIONAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatalf("Run() returned an error with only an optional check failing: %v", err)
+	}
+
+	if aHealthManager.GetState() != DEGRADED {
+		t.Fatalf("GetState() returned unexpected value with only an optional check failing: %s", aHealthManager.GetState())
+	}
+
+	if aHealthManager.GetHealth() != true {
+		t.Fatal("GetHealth() returned unexpected value with only an optional check failing")
+	}
+}
+
+func TestHealthManagerRunCriticalFail(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck(), WithCriticality(OPTIONAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Register(NewFailCheck(), WithCriticality(CRITICAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Run() did not returned expected error for a failing critical check\nexpected: %v\ngot: %v", ErrTimeout, err)
+	}
+
+	if aHealthManager.GetState() != UNHEALTHY {
+		t.Fatalf("GetState() returned unexpected value with a critical check failing: %s", aHealthManager.GetState())
+	}
+}
+
+func TestHealthManagerHooks(t *testing.T) {
+	aHealthManager, err := New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.Register(NewTestCheck(), WithName("wibble"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type hookKey struct{}
+	var started, finished atomic.Int32
+	aHealthManager.AddHooks(Hooks{
+		CheckStarted: func(ctx context.Context, report CheckReport) context.Context {
+			started.Add(1)
+			return context.WithValue(ctx, hookKey{}, report.Name)
+		},
+		CheckFinished: func(ctx context.Context, report CheckReport) {
+			if ctx.Value(hookKey{}) != "wibble" || !report.Passed() {
+				t.Errorf("CheckFinished hook called with unexpected arguments: %v %+v", ctx.Value(hookKey{}), report)
+			}
+			finished.Add(1)
+		},
+	})
+	aHealthManager.AddHooks(Hooks{})
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if started.Load() != 1 || finished.Load() != 1 {
+		t.Fatalf("hooks were not called once per check attempt: started %d, finished %d", started.Load(), finished.Load())
+	}
+}
+
 func TestHealthManagerMultipleChecks(t *testing.T) {
 	aHealthManager, err := New(time.Second, time.Minute)
 	if err != nil {
@@ -240,3 +640,244 @@ func TestHealthManagerMultipleChecks(t *testing.T) {
 		t.Fatalf("Register() did not successfully register all checks")
 	}
 }
+
+// gateCheck signals entered at each attempt and waits for release, failing its first attempt.
+type gateCheck struct {
+	attempts atomic.Int32
+	entered  chan struct{}
+	release  chan struct{}
+}
+
+func (c *gateCheck) GetImp() checks.Implementation {
+	return TEST
+}
+
+func (c *gateCheck) Check(ctx context.Context) checks.Result {
+	c.entered <- struct{}{}
+	<-c.release
+
+	if c.attempts.Add(1) == 1 {
+		return checks.Result{Status: checks.DONE, Err: ErrFailCheck}
+	}
+	return checks.Result{Status: checks.DONE}
+}
+
+func (c *gateCheck) Cleanup() {}
+
+func TestHealthManagerAddHooksDuringRun(t *testing.T) {
+	aHealthManager, err := New(10*time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := &gateCheck{entered: make(chan struct{}), release: make(chan struct{})}
+	err = aHealthManager.RegisterV2(aCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- aHealthManager.Run()
+	}()
+
+	// The first round is running, so the hooks are added whilst Run holds its lock.
+	<-aCheck.entered
+	var finished atomic.Int32
+	aHealthManager.AddHooks(Hooks{
+		CheckFinished: func(ctx context.Context, report CheckReport) {
+			finished.Add(1)
+		},
+	})
+	aCheck.release <- struct{}{}
+
+	// The first round fails, so the hooks are called from the second.
+	<-aCheck.entered
+	aCheck.release <- struct{}{}
+
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if finished.Load() != 1 {
+		t.Fatalf("hooks added whilst Run() was retrying checks were called %d times, expected once", finished.Load())
+	}
+}
+
+// blockCheck returns first from its first attempt and then blocks until its context is done.
+type blockCheck struct {
+	first    error
+	attempts atomic.Int32
+	blocked  chan struct{}
+}
+
+func (c *blockCheck) GetImp() checks.Implementation {
+	return TEST
+}
+
+func (c *blockCheck) Check(ctx context.Context) checks.Result {
+	attempt := c.attempts.Add(1)
+	if attempt == 1 {
+		return checks.Result{Status: checks.DONE, Err: c.first}
+	}
+
+	if attempt == 2 {
+		close(c.blocked)
+	}
+	<-ctx.Done()
+	return checks.Result{Status: checks.DONE, Err: ctx.Err()}
+}
+
+func (c *blockCheck) Cleanup() {}
+
+func TestHealthManagerStopAbandonsRound(t *testing.T) {
+	aHealthManager, err := New(10*time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := &blockCheck{blocked: make(chan struct{})}
+	err = aHealthManager.RegisterV2(aCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var finished, abandoned atomic.Int32
+	aHealthManager.AddHooks(Hooks{
+		CheckFinished: func(ctx context.Context, report CheckReport) {
+			finished.Add(1)
+			if report.Abandoned {
+				abandoned.Add(1)
+			}
+		},
+	})
+
+	err = aHealthManager.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	<-aCheck.blocked
+	aHealthManager.Stop()
+
+	if !aHealthManager.GetHealth() {
+		t.Fatal("GetHealth() returned unexpected value after stopping during a round")
+	}
+
+	for _, report := range aHealthManager.GetReports() {
+		if !report.Passed() || report.Result.Status != checks.DONE {
+			t.Fatalf("GetReports() returned an abandoned result after stopping during a round: %+v", report)
+		}
+	}
+
+	if finished.Load() != 2 || abandoned.Load() != 1 {
+		t.Fatalf("hooks were called with %d abandoned reports of %d, expected 1 of 2", abandoned.Load(), finished.Load())
+	}
+}
+
+// holdCheck passes, waiting for release after signalling entered whilst hold is set.
+type holdCheck struct {
+	hold    atomic.Bool
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c *holdCheck) GetImp() checks.Implementation {
+	return TEST
+}
+
+func (c *holdCheck) Check(ctx context.Context) checks.Result {
+	if c.hold.Load() {
+		c.entered <- struct{}{}
+		<-c.release
+	}
+	return checks.Result{Status: checks.DONE}
+}
+
+func (c *holdCheck) Cleanup() {}
+
+func TestHealthManagerRunContextWhilstMonitoring(t *testing.T) {
+	aHealthManager, err := New(time.Hour, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	aCheck := &holdCheck{entered: make(chan struct{}), release: make(chan struct{})}
+	err = aHealthManager.RegisterV2(aCheck)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !waitForHealth(aHealthManager, true) {
+		t.Fatal("GetHealth() did not return true whilst monitoring a passing check")
+	}
+
+	aCheck.hold.Store(true)
+	done := make(chan error, 1)
+	go func() {
+		done <- aHealthManager.RunContext(context.Background())
+	}()
+
+	<-aCheck.entered
+	healthy := aHealthManager.GetHealth()
+	aCheck.release <- struct{}{}
+
+	err = <-done
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !healthy {
+		t.Fatal("GetHealth() returned unexpected value whilst RunContext() ran during monitoring")
+	}
+}
+
+func TestHealthManagerRunContextDiscardsInterruptedRound(t *testing.T) {
+	aHealthManager, err := New(10*time.Millisecond, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	errRefused := errors.New("connection refused")
+	aCheck := &blockCheck{first: errRefused, blocked: make(chan struct{})}
+	err = aHealthManager.RegisterV2(aCheck, WithName("slow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-aCheck.blocked
+		cancel()
+	}()
+
+	err = aHealthManager.RunContext(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RunContext() did not returned expected error for a cancelled context\nexpected: %v\ngot: %v", context.Canceled, err)
+	}
+
+	if !strings.Contains(err.Error(), "slow:"+errRefused.Error()) {
+		t.Fatalf("RunContext() error did not include the check's last failure before the context was cancelled: %v", err)
+	}
+
+	report, ok := aHealthManager.GetReport("slow")
+	if !ok {
+		t.Fatal("GetReport() did not return a report for a registered check")
+	}
+
+	if !errors.Is(report.Result.Err, errRefused) || report.Result.Status != checks.DONE {
+		t.Fatalf("GetReport() returned the result of a round interrupted by the context: %+v", report)
+	}
+}
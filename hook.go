@@ -0,0 +1,32 @@
+package healthcheck
+
+import "context"
+
+// Hooks are called by a health manager as it runs checks, allowing integrations,
+// e.g. metrics and tracing, to observe every check attempt. Nil hooks are skipped.
+type Hooks struct {
+	// RoundStarted is called before a round of checks is attempted.
+	// The returned context is passed to later hooks and every check in the round.
+	RoundStarted func(ctx context.Context) context.Context
+	// RoundFinished is called after every check in a round has been attempted and the health
+	// manager's state updated, with the context returned by RoundStarted and the state
+	// resulting from the round's results.
+	RoundFinished func(ctx context.Context, state HealthState)
+	// CheckStarted is called before a check is attempted, with the check's report prior to the attempt.
+	// The returned context is passed to later hooks and the check.
+	CheckStarted func(ctx context.Context, report CheckReport) context.Context
+	// CheckFinished is called after every check in a round has been attempted, before RoundFinished,
+	// with the context returned by CheckStarted and the check's report including the attempt's result.
+	// The report has Abandoned set if the round was abandoned and the result not recorded.
+	CheckFinished func(ctx context.Context, report CheckReport)
+}
+
+// AddHooks adds hooks to the health manager, which are called for every subsequent round of checks.
+// Hooks may be added whilst checks are running, e.g. after Run has been started in a goroutine.
+// CheckStarted hooks are called concurrently for different checks.
+func (hm *HealthManager) AddHooks(h Hooks) {
+	hm.stateMtx.Lock()
+	defer hm.stateMtx.Unlock()
+
+	hm.hooks = append(hm.hooks, h)
+}
@@ -0,0 +1,173 @@
+// Package otelhealth instruments a health manager with OpenTelemetry tracing and metrics.
+package otelhealth
+
+import (
+	"context"
+
+	"github.com/LS6-Events/healthcheck"
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer and meter.
+const ScopeName = "github.com/LS6-Events/healthcheck/otelhealth"
+
+const (
+	NameKey           = attribute.Key("healthcheck.name")
+	ImplementationKey = attribute.Key("healthcheck.implementation")
+	CriticalityKey    = attribute.Key("healthcheck.criticality")
+	StatusKey         = attribute.Key("healthcheck.status")
+	ErrorKey          = attribute.Key("healthcheck.error")
+	OutcomeKey        = attribute.Key("healthcheck.outcome")
+	StateKey          = attribute.Key("healthcheck.state")
+	AbandonedKey      = attribute.Key("healthcheck.abandoned")
+)
+
+const (
+	// the check attempt passed.
+	OutcomePass = "pass"
+	// the check attempt failed.
+	OutcomeFail = "fail"
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the TracerProvider used to create spans, defaults to otel.GetTracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the MeterProvider used to record metrics, defaults to otel.GetMeterProvider().
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+type instrumentation struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	attempts metric.Int64Counter
+}
+
+// Instrument adds hooks to hm which trace and measure every subsequent round of checks.
+//
+// A "healthcheck.round" span is created for each round of checks, e.g. each iteration of Run,
+// with a child "healthcheck.check" span for each check attempt. Check spans carry the check's
+// name, implementation and criticality, and on failure the check's error.
+// The healthcheck.check.duration histogram and healthcheck.check.attempts counter record
+// the latency and outcome of each check attempt.
+func Instrument(hm *healthcheck.HealthManager, opts ...Option) error {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram("healthcheck.check.duration",
+		metric.WithDescription("Latency of check attempts."),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating check duration histogram")
+	}
+
+	attempts, err := meter.Int64Counter("healthcheck.check.attempts",
+		metric.WithDescription("Number of check attempts, by outcome."),
+		metric.WithUnit("{attempt}"),
+	)
+	if err != nil {
+		return errors.Wrap(err, "error creating check attempts counter")
+	}
+
+	inst := &instrumentation{
+		tracer:   cfg.tracerProvider.Tracer(ScopeName),
+		duration: duration,
+		attempts: attempts,
+	}
+
+	hm.AddHooks(healthcheck.Hooks{
+		RoundStarted:  inst.roundStarted,
+		RoundFinished: inst.roundFinished,
+		CheckStarted:  inst.checkStarted,
+		CheckFinished: inst.checkFinished,
+	})
+
+	return nil
+}
+
+func (i *instrumentation) roundStarted(ctx context.Context) context.Context {
+	ctx, _ = i.tracer.Start(ctx, "healthcheck.round", trace.WithSpanKind(trace.SpanKindInternal))
+	return ctx
+}
+
+func (i *instrumentation) roundFinished(ctx context.Context, state healthcheck.HealthState) {
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(StateKey.String(string(state)))
+	if state == healthcheck.UNHEALTHY {
+		span.SetStatus(codes.Error, "critical checks failing")
+	}
+	span.End()
+}
+
+func (i *instrumentation) checkStarted(ctx context.Context, report healthcheck.CheckReport) context.Context {
+	ctx, _ = i.tracer.Start(ctx, "healthcheck.check",
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(checkAttributes(report)...),
+	)
+	return ctx
+}
+
+func (i *instrumentation) checkFinished(ctx context.Context, report healthcheck.CheckReport) {
+	span := trace.SpanFromContext(ctx)
+	// Hooks are called once the whole round has finished, the span ends with the attempt.
+	defer span.End(trace.WithTimestamp(report.Result.Timestamp))
+
+	// An abandoned attempt's failure is that of its cancelled context, not the dependency.
+	if report.Abandoned {
+		span.SetAttributes(AbandonedKey.Bool(true))
+		return
+	}
+
+	attrs := checkAttributes(report)
+
+	outcome := OutcomePass
+	if !report.Passed() {
+		outcome = OutcomeFail
+	}
+
+	i.duration.Record(ctx, report.Result.Latency.Seconds(), metric.WithAttributes(attrs...))
+	i.attempts.Add(ctx, 1, metric.WithAttributes(append(attrs, OutcomeKey.String(outcome))...))
+
+	span.SetAttributes(StatusKey.String(string(report.Result.Status)))
+	if report.Result.Err != nil {
+		span.SetAttributes(ErrorKey.String(report.Result.Err.Error()))
+		span.RecordError(report.Result.Err)
+		span.SetStatus(codes.Error, report.Result.Err.Error())
+	}
+}
+
+// checkAttributes returns the attributes identifying the check described by report.
+func checkAttributes(report healthcheck.CheckReport) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		NameKey.String(report.Name),
+		ImplementationKey.String(string(report.Imp)),
+		CriticalityKey.String(string(report.Criticality)),
+	}
+}
@@ -0,0 +1,190 @@
+package otelhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LS6-Events/healthcheck"
+	"github.com/LS6-Events/healthcheck/checks"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const TEST checks.Implementation = "test"
+
+var errTestCheck = errors.New("test check is configured to fail")
+
+type testCheck struct {
+	fail bool
+}
+
+func (c *testCheck) GetImp() checks.Implementation {
+	return TEST
+}
+
+func (c *testCheck) Check(ctx context.Context) checks.Result {
+	res := checks.Result{
+		Status:    checks.DONE,
+		Latency:   10 * time.Millisecond,
+		Timestamp: time.Now(),
+	}
+
+	if c.fail {
+		res.Err = errTestCheck
+	}
+
+	return res
+}
+
+func (c *testCheck) Cleanup() {}
+
+// attributeValue returns the value of key in attrs, or an empty string.
+func attributeValue(attrs []attribute.KeyValue, key attribute.Key) string {
+	for _, kv := range attrs {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestInstrument(t *testing.T) {
+	aHealthManager, err := healthcheck.New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.RegisterV2(&testCheck{}, healthcheck.WithName("pass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.RegisterV2(&testCheck{fail: true}, healthcheck.WithName("fail"), healthcheck.WithCriticality(healthcheck.OPTIONAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	err = Instrument(aHealthManager, WithTracerProvider(tp), WithMeterProvider(mp))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stubs := spans.GetSpans()
+	if len(stubs) != 3 {
+		t.Fatalf("Instrument() did not create a span per round and check: %d spans", len(stubs))
+	}
+
+	var round tracetest.SpanStub
+	checkSpans := make(map[string]tracetest.SpanStub)
+	for _, s := range stubs {
+		if s.Name == "healthcheck.round" {
+			round = s
+		} else {
+			checkSpans[attributeValue(s.Attributes, NameKey)] = s
+		}
+	}
+
+	if attributeValue(round.Attributes, StateKey) != string(healthcheck.DEGRADED) {
+		t.Fatalf("round span has unexpected attributes: %v", round.Attributes)
+	}
+
+	failSpan, ok := checkSpans["fail"]
+	if !ok || failSpan.Parent.SpanID() != round.SpanContext.SpanID() {
+		t.Fatalf("check span is not a child of the round span: %+v", failSpan)
+	}
+
+	if failSpan.Status.Code != codes.Error || attributeValue(failSpan.Attributes, ErrorKey) != errTestCheck.Error() || attributeValue(failSpan.Attributes, ImplementationKey) != string(TEST) {
+		t.Fatalf("failing check span has unexpected status or attributes: %v %v", failSpan.Status, failSpan.Attributes)
+	}
+
+	if checkSpans["pass"].Status.Code == codes.Error {
+		t.Fatalf("passing check span has unexpected status: %v", checkSpans["pass"].Status)
+	}
+
+	var rm metricdata.ResourceMetrics
+	err = reader.Collect(context.Background(), &rm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make(map[string]bool)
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+
+			switch data := m.Data.(type) {
+			case metricdata.Histogram[float64]:
+				if len(data.DataPoints) != 2 {
+					t.Fatalf("%s has unexpected data points: %v", m.Name, data.DataPoints)
+				}
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					name, _ := dp.Attributes.Value(NameKey)
+					outcome, _ := dp.Attributes.Value(OutcomeKey)
+					if (name.AsString() == "fail") != (outcome.AsString() == OutcomeFail) || dp.Value != 1 {
+						t.Fatalf("%s has unexpected data point: %v", m.Name, dp)
+					}
+				}
+			}
+		}
+	}
+
+	if !found["healthcheck.check.duration"] || !found["healthcheck.check.attempts"] {
+		t.Fatalf("Instrument() did not record expected metrics: %v", found)
+	}
+}
+
+func TestInstrumentTwice(t *testing.T) {
+	aHealthManager, err := healthcheck.New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.RegisterV2(&testCheck{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporters := []*tracetest.InMemoryExporter{tracetest.NewInMemoryExporter(), tracetest.NewInMemoryExporter()}
+	for _, spans := range exporters {
+		err = Instrument(aHealthManager, WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spans))))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each instrumentation must end the spans it started, which are only exported once ended.
+	for i, spans := range exporters {
+		names := make(map[string]int)
+		for _, s := range spans.GetSpans() {
+			names[s.Name]++
+		}
+
+		if names["healthcheck.round"] != 1 || names["healthcheck.check"] != 1 {
+			t.Fatalf("Instrument() %d did not end its round and check spans: %v", i, names)
+		}
+	}
+}
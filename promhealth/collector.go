@@ -0,0 +1,144 @@
+// Package promhealth exports the state of a health manager as Prometheus metrics.
+package promhealth
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/LS6-Events/healthcheck"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "healthcheck"
+
+var checkLabels = []string{"name", "implementation"}
+
+// Collector is a prometheus.Collector exporting a health manager's state and the results of its checks.
+//
+// Exports, labelled by each check's name and implementation:
+//   - healthcheck_check_up, whether the check's last attempt passed.
+//   - healthcheck_check_duration_seconds, a histogram of check attempt latencies.
+//   - healthcheck_check_last_success_timestamp_seconds, when the check last passed.
+//   - healthcheck_check_failures_total, the number of failed check attempts.
+//
+// As well as healthcheck_state, labelled by state, which is 1 for the health manager's current state.
+type Collector struct {
+	hm          *healthcheck.HealthManager
+	up          *prometheus.Desc
+	lastSuccess *prometheus.Desc
+	state       *prometheus.Desc
+	duration    *prometheus.HistogramVec
+	failures    *prometheus.CounterVec
+
+	mtx           sync.Mutex
+	lastSuccesses map[string]time.Time
+}
+
+// NewCollector returns a new Collector for hm.
+// The collector observes every check attempt made by hm after it is created.
+func NewCollector(hm *healthcheck.HealthManager) *Collector {
+	c := &Collector{
+		hm: hm,
+		up: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "check", "up"),
+			"Whether the check's last attempt passed.",
+			checkLabels, nil,
+		),
+		lastSuccess: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "check", "last_success_timestamp_seconds"),
+			"Unix time the check last passed.",
+			checkLabels, nil,
+		),
+		state: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "state"),
+			"Whether the health manager is in the labelled state.",
+			[]string{"state"}, nil,
+		),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "check",
+			Name:      "duration_seconds",
+			Help:      "Latency of check attempts.",
+			Buckets:   prometheus.DefBuckets,
+		}, checkLabels),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "check",
+			Name:      "failures_total",
+			Help:      "Number of failed check attempts.",
+		}, checkLabels),
+		lastSuccesses: make(map[string]time.Time),
+	}
+
+	for _, report := range hm.GetReports() {
+		if report.Passed() {
+			c.lastSuccesses[report.Name] = report.Result.Timestamp
+		}
+	}
+
+	hm.AddHooks(healthcheck.Hooks{
+		CheckFinished: c.observe,
+	})
+
+	return c
+}
+
+// observe records the result of a check attempt, unless it was abandoned.
+func (c *Collector) observe(ctx context.Context, report healthcheck.CheckReport) {
+	if report.Abandoned {
+		return
+	}
+
+	c.duration.WithLabelValues(report.Name, string(report.Imp)).Observe(report.Result.Latency.Seconds())
+
+	failures := c.failures.WithLabelValues(report.Name, string(report.Imp))
+	if !report.Passed() {
+		failures.Inc()
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.lastSuccesses[report.Name] = report.Result.Timestamp
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.up
+	ch <- c.lastSuccess
+	ch <- c.state
+	c.duration.Describe(ch)
+	c.failures.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, report := range c.hm.GetReports() {
+		up := 0.0
+		if report.Passed() {
+			up = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.up, prometheus.GaugeValue, up, report.Name, string(report.Imp))
+
+		if t, ok := c.lastSuccesses[report.Name]; ok {
+			ch <- prometheus.MustNewConstMetric(c.lastSuccess, prometheus.GaugeValue, float64(t.UnixNano())/1e9, report.Name, string(report.Imp))
+		}
+	}
+
+	current := c.hm.GetState()
+	for _, state := range []healthcheck.HealthState{healthcheck.HEALTHY, healthcheck.DEGRADED, healthcheck.UNHEALTHY} {
+		value := 0.0
+		if state == current {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, value, string(state))
+	}
+
+	c.duration.Collect(ch)
+	c.failures.Collect(ch)
+}
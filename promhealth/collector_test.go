@@ -0,0 +1,141 @@
+package promhealth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/LS6-Events/healthcheck"
+	"github.com/LS6-Events/healthcheck/checks"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+const TEST checks.Implementation = "test"
+
+var errTestCheck = errors.New("test check is configured to fail")
+
+type testCheck struct {
+	fail bool
+}
+
+func (c *testCheck) GetImp() checks.Implementation {
+	return TEST
+}
+
+func (c *testCheck) Check(ctx context.Context) checks.Result {
+	res := checks.Result{
+		Status:    checks.DONE,
+		Latency:   10 * time.Millisecond,
+		Timestamp: time.Now(),
+	}
+
+	if c.fail {
+		res.Err = errTestCheck
+	}
+
+	return res
+}
+
+func (c *testCheck) Cleanup() {}
+
+// gather returns the metric families collected by c, keyed by name.
+func gather(t *testing.T, c *Collector) map[string]*dto.MetricFamily {
+	t.Helper()
+
+	registry := prometheus.NewPedanticRegistry()
+	err := registry.Register(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	byName := make(map[string]*dto.MetricFamily)
+	for _, f := range families {
+		byName[f.GetName()] = f
+	}
+	return byName
+}
+
+// metricFor returns the metric in f labelled with name, or nil.
+func metricFor(f *dto.MetricFamily, name string) *dto.Metric {
+	if f == nil {
+		return nil
+	}
+
+	for _, m := range f.GetMetric() {
+		for _, l := range m.GetLabel() {
+			if l.GetName() == "name" && l.GetValue() == name {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+func TestCollector(t *testing.T) {
+	aHealthManager, err := healthcheck.New(time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer aHealthManager.Cleanup()
+
+	err = aHealthManager.RegisterV2(&testCheck{}, healthcheck.WithName("pass"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = aHealthManager.RegisterV2(&testCheck{fail: true}, healthcheck.WithName("fail"), healthcheck.WithCriticality(healthcheck.OPTIONAL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	aCollector := NewCollector(aHealthManager)
+
+	err = aHealthManager.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	families := gather(t, aCollector)
+
+	if m := metricFor(families["healthcheck_check_up"], "pass"); m == nil || m.GetGauge().GetValue() != 1 {
+		t.Fatalf("healthcheck_check_up returned unexpected value for a passing check: %v", m)
+	}
+
+	if m := metricFor(families["healthcheck_check_up"], "fail"); m == nil || m.GetGauge().GetValue() != 0 {
+		t.Fatalf("healthcheck_check_up returned unexpected value for a failing check: %v", m)
+	}
+
+	if m := metricFor(families["healthcheck_check_failures_total"], "fail"); m == nil || m.GetCounter().GetValue() != 1 {
+		t.Fatalf("healthcheck_check_failures_total returned unexpected value for a failing check: %v", m)
+	}
+
+	if m := metricFor(families["healthcheck_check_duration_seconds"], "pass"); m == nil || m.GetHistogram().GetSampleCount() != 1 {
+		t.Fatalf("healthcheck_check_duration_seconds returned unexpected value: %v", m)
+	}
+
+	if m := metricFor(families["healthcheck_check_last_success_timestamp_seconds"], "pass"); m == nil || m.GetGauge().GetValue() <= 0 {
+		t.Fatalf("healthcheck_check_last_success_timestamp_seconds returned unexpected value: %v", m)
+	}
+
+	if m := metricFor(families["healthcheck_check_last_success_timestamp_seconds"], "fail"); m != nil {
+		t.Fatalf("healthcheck_check_last_success_timestamp_seconds reported a check which never passed: %v", m)
+	}
+
+	for _, m := range families["healthcheck_state"].GetMetric() {
+		expected := 0.0
+		if m.GetLabel()[0].GetValue() == string(healthcheck.DEGRADED) {
+			expected = 1
+		}
+
+		if m.GetGauge().GetValue() != expected {
+			t.Fatalf("healthcheck_state returned unexpected value: %v", m)
+		}
+	}
+}
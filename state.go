@@ -0,0 +1,44 @@
+package healthcheck
+
+type HealthState string
+type Criticality string
+
+const (
+	// every check is passing.
+	HEALTHY HealthState = "healthy"
+	// every critical check is passing, but one or more optional checks are failing.
+	DEGRADED HealthState = "degraded"
+	// one or more critical checks are failing, or have not yet been attempted.
+	UNHEALTHY HealthState = "unhealthy"
+)
+
+const (
+	// the check must pass for the health manager to be healthy, the default.
+	CRITICAL Criticality = "critical"
+	// the check failing only degrades the health manager.
+	OPTIONAL Criticality = "optional"
+)
+
+// WithCriticality sets whether the check must pass for the health manager to be healthy.
+// Checks are CRITICAL unless registered with WithCriticality(OPTIONAL).
+func WithCriticality(criticality Criticality) RegisterOption {
+	return func(rc *registeredCheck) {
+		rc.criticality = criticality
+	}
+}
+
+// stateOf returns the overall state of the checks described by reports.
+func stateOf(reports []CheckReport) HealthState {
+	state := HEALTHY
+	for _, report := range reports {
+		if report.Passed() {
+			continue
+		}
+
+		if report.Criticality != OPTIONAL {
+			return UNHEALTHY
+		}
+		state = DEGRADED
+	}
+	return state
+}